@@ -20,4 +20,8 @@ const (
 	AttrIP             = "ip"
 	AttrAppVersion     = "app_version"
 	AttrPlatform       = "platform"
+	AttrService        = "service"
+	AttrEnvironment    = "environment"
+	AttrHostname       = "hostname"
+	AttrSDKVersion     = "sdk_version"
 )
@@ -0,0 +1,406 @@
+package togglr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-faster/jx"
+)
+
+// AttributeValidation selects how the client handles attributes that are not
+// in canonical form before they are sent.
+type AttributeValidation int
+
+const (
+	// AttributeValidationOff sends attributes as they are. Attributes that
+	// cannot be encoded are logged and left out.
+	AttributeValidationOff AttributeValidation = iota
+	// AttributeValidationLenient normalizes attributes and logs the ones that
+	// are invalid or exceed the limits. Invalid attributes are still sent;
+	// over-long names are cut and attributes beyond MaxAttributes dropped.
+	AttributeValidationLenient
+	// AttributeValidationStrict normalizes attributes and fails the operation
+	// with ErrInvalidAttribute if any of them is invalid or exceeds the limits.
+	AttributeValidationStrict
+)
+
+const (
+	MaxAttributes           = 128
+	MaxAttributeKeyLength   = 128
+	MaxAttributeValueLength = 1024
+	maxAge                  = 150
+)
+
+// AttributeError describes an attribute that is invalid or was changed to
+// fit the limits. It matches ErrInvalidAttribute.
+type AttributeError struct {
+	Attribute string
+	Value     any
+	Reason    string
+}
+
+func (e *AttributeError) Error() string {
+	return fmt.Sprintf("attribute %q: %s", e.Attribute, e.Reason)
+}
+
+func (e *AttributeError) Unwrap() error {
+	return ErrInvalidAttribute
+}
+
+type attributeRule func(v any) (any, string)
+
+var attributeRules = map[string]attributeRule{
+	AttrUserID:         normalizeUserID,
+	AttrUserEmail:      normalizeEmail,
+	AttrUserAnonymous:  normalizeBool,
+	AttrCountryCode:    normalizeCountryCode,
+	AttrLanguage:       normalizeLanguage,
+	AttrAge:            normalizeAge,
+	AttrIP:             normalizeIP,
+	AttrDeviceType:     lowerString,
+	AttrConnectionType: lowerString,
+	AttrPlatform:       lowerString,
+	AttrGender:         lowerString,
+	AttrRegion:         trimString,
+	AttrCity:           trimString,
+	AttrManufacturer:   trimString,
+	AttrOS:             trimString,
+	AttrOSVersion:      trimString,
+	AttrBrowser:        trimString,
+	AttrBrowserVersion: trimString,
+	AttrAppVersion:     trimString,
+}
+
+// Normalize returns a copy of r with well-known attributes in canonical form:
+// country codes upper case, languages as "en-US", emails trimmed and lower
+// case, IPs and ages parsed, times as RFC 3339 in UTC, and strings cut to
+// MaxAttributeValueLength. Names longer than MaxAttributeKeyLength are cut,
+// and attributes beyond MaxAttributes, in name order, are dropped. The
+// returned error lists every attribute that is invalid, was changed to fit the
+// limits or was dropped; invalid attributes are kept in the result, converted
+// to a string if they cannot be encoded as JSON.
+func (r RequestContext) Normalize() (RequestContext, error) {
+	return normalizeAttributeSet(r, nil)
+}
+
+// normalizeAttributeSet normalizes attrs, counting the attributes of defaults
+// that attrs does not override towards MaxAttributes.
+func normalizeAttributeSet(attrs, defaults map[string]any) (RequestContext, error) {
+	limit := MaxAttributes
+	for k := range defaults {
+		if _, ok := attrs[k]; !ok {
+			limit--
+		}
+	}
+
+	out := make(RequestContext, len(attrs))
+	var errs []error
+
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		name := key
+		if len(name) > MaxAttributeKeyLength {
+			name = truncateString(name, MaxAttributeKeyLength)
+			errs = append(errs, &AttributeError{Attribute: key, Value: attrs[key],
+				Reason: fmt.Sprintf("name longer than %d bytes, truncated", MaxAttributeKeyLength)})
+
+			if _, ok := out[name]; ok {
+				errs = append(errs, &AttributeError{Attribute: key, Value: attrs[key],
+					Reason: fmt.Sprintf("truncated name %q already used, dropped", name)})
+
+				continue
+			}
+		}
+
+		if len(out) >= limit {
+			errs = append(errs, &AttributeError{Attribute: key, Value: attrs[key],
+				Reason: fmt.Sprintf("more than %d attributes, dropped", MaxAttributes)})
+
+			continue
+		}
+
+		v, reason := normalizeValue(name, attrs[key])
+		out[name] = v
+
+		if reason != "" {
+			errs = append(errs, &AttributeError{Attribute: key, Value: attrs[key], Reason: reason})
+		}
+	}
+
+	return out, errors.Join(errs...)
+}
+
+func normalizeValue(key string, v any) (any, string) {
+	switch t := v.(type) {
+	case time.Time:
+		v = t.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if t != nil {
+			v = t.UTC().Format(time.RFC3339Nano)
+		}
+	case net.IP:
+		v = t.String()
+	case netip.Addr:
+		v = t.String()
+	}
+
+	if rule, ok := attributeRules[key]; ok {
+		normalized, reason := rule(v)
+		if reason != "" {
+			return encodable(v), reason
+		}
+		v = normalized
+	}
+
+	if s, ok := v.(string); ok && len(s) > MaxAttributeValueLength {
+		return truncateString(s, MaxAttributeValueLength), fmt.Sprintf("value longer than %d bytes, truncated", MaxAttributeValueLength)
+	}
+
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprint(v), "value cannot be encoded as JSON, sent as string"
+	}
+
+	return v, ""
+}
+
+// encodable returns v, or its string form if v cannot be encoded as JSON.
+func encodable(v any) any {
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return v
+}
+
+func truncateString(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
+
+func trimString(v any) (any, string) {
+	s, ok := v.(string)
+	if !ok {
+		return v, fmt.Sprintf("expected a string, got %T", v)
+	}
+
+	return strings.TrimSpace(s), ""
+}
+
+func lowerString(v any) (any, string) {
+	s, reason := trimString(v)
+	if reason != "" {
+		return v, reason
+	}
+
+	return strings.ToLower(s.(string)), ""
+}
+
+func normalizeUserID(v any) (any, string) {
+	switch t := v.(type) {
+	case string:
+		if s := strings.TrimSpace(t); s != "" {
+			return s, ""
+		}
+
+		return v, "empty user id"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(t), ""
+	case fmt.Stringer:
+		return t.String(), ""
+	default:
+		return v, fmt.Sprintf("expected a string, got %T", v)
+	}
+}
+
+func normalizeEmail(v any) (any, string) {
+	s, reason := lowerString(v)
+	if reason != "" {
+		return v, reason
+	}
+
+	local, domain, ok := strings.Cut(s.(string), "@")
+	if !ok || local == "" || domain == "" || strings.Contains(domain, "@") {
+		return v, "not an email address"
+	}
+
+	return s, ""
+}
+
+func normalizeBool(v any) (any, string) {
+	switch t := v.(type) {
+	case bool:
+		return t, ""
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(t)); err == nil {
+			return b, ""
+		}
+	}
+
+	return v, fmt.Sprintf("expected a boolean, got %v", v)
+}
+
+func normalizeCountryCode(v any) (any, string) {
+	s, reason := trimString(v)
+	if reason != "" {
+		return v, reason
+	}
+
+	code := strings.ToUpper(s.(string))
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return v, "not an ISO 3166-1 alpha-2 country code"
+	}
+
+	return code, ""
+}
+
+// normalizeLanguage formats BCP 47 tags as "en-US" or "zh-Hant-TW".
+func normalizeLanguage(v any) (any, string) {
+	s, reason := trimString(v)
+	if reason != "" {
+		return v, reason
+	}
+
+	parts := strings.FieldsFunc(s.(string), func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 || len(parts[0]) < 2 || len(parts[0]) > 3 {
+		return v, "not a language tag"
+	}
+
+	for i, p := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(p)
+		case len(p) == 2:
+			parts[i] = strings.ToUpper(p)
+		case len(p) == 4:
+			parts[i] = strings.ToUpper(p[:1]) + strings.ToLower(p[1:])
+		default:
+			parts[i] = strings.ToLower(p)
+		}
+	}
+
+	return strings.Join(parts, "-"), ""
+}
+
+func normalizeAge(v any) (any, string) {
+	var age float64
+
+	switch t := v.(type) {
+	case int:
+		age = float64(t)
+	case int8:
+		age = float64(t)
+	case int16:
+		age = float64(t)
+	case int32:
+		age = float64(t)
+	case int64:
+		age = float64(t)
+	case uint:
+		age = float64(t)
+	case uint8:
+		age = float64(t)
+	case uint16:
+		age = float64(t)
+	case uint32:
+		age = float64(t)
+	case uint64:
+		age = float64(t)
+	case float32:
+		age = float64(t)
+	case float64:
+		age = t
+	case json.Number:
+		f, err := t.Float64()
+		if err != nil {
+			return v, "not a number"
+		}
+		age = f
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		if err != nil {
+			return v, "not a number"
+		}
+		age = f
+	default:
+		return v, fmt.Sprintf("expected a number, got %T", v)
+	}
+
+	if age != math.Trunc(age) || age < 0 || age > maxAge {
+		return v, fmt.Sprintf("age must be a whole number between 0 and %d", maxAge)
+	}
+
+	return int(age), ""
+}
+
+func normalizeIP(v any) (any, string) {
+	s, reason := trimString(v)
+	if reason != "" {
+		return v, reason
+	}
+
+	addr, err := netip.ParseAddr(s.(string))
+	if err != nil {
+		return v, "not an IP address"
+	}
+
+	return addr.Unmap().String(), ""
+}
+
+// prepareAttributes applies Config.AttributeValidation and then the PII
+// policy to attrs.
+func (c *Client) prepareAttributes(op Operation, featureKey string, attrs map[string]any) (map[string]any, error) {
+	attrs, err := c.normalizeAttributes(op, featureKey, attrs)
+	if err != nil {
+		return attrs, err
+	}
+
+	return c.pii.apply(attrs), nil
+}
+
+func (c *Client) normalizeAttributes(op Operation, featureKey string, attrs map[string]any) (map[string]any, error) {
+	if c.cfg.AttributeValidation == AttributeValidationOff || len(attrs) == 0 {
+		return attrs, nil
+	}
+
+	normalized, err := normalizeAttributeSet(attrs, c.defaultAttrs)
+	if err != nil {
+		if c.cfg.AttributeValidation == AttributeValidationStrict {
+			return attrs, err
+		}
+
+		c.logger.Warn("attributes normalized",
+			"operation", op, "feature_key", featureKey, "error", err)
+	}
+
+	return normalized, nil
+}
+
+// encodeAttributes encodes attrs for the API. Attributes that cannot be
+// encoded are logged and left out.
+func encodeAttributes(attrs map[string]any, logger Logger) map[string]jx.Raw {
+	encoded := make(map[string]jx.Raw, len(attrs))
+
+	for k, v := range attrs {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			logger.Warn("attribute cannot be encoded, not sent", "attribute", k, "error", err)
+
+			continue
+		}
+		encoded[k] = jx.Raw(raw)
+	}
+
+	return encoded
+}
@@ -0,0 +1,194 @@
+package togglr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestContextNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value any
+		want  any
+		valid bool
+	}{
+		{"country upper case", AttrCountryCode, " us ", "US", true},
+		{"country invalid", AttrCountryCode, "USA", "USA", false},
+		{"language", AttrLanguage, "en_us", "en-US", true},
+		{"language with script", AttrLanguage, "ZH-hant-tw", "zh-Hant-TW", true},
+		{"email", AttrUserEmail, "  John.Doe@Example.COM ", "john.doe@example.com", true},
+		{"email invalid", AttrUserEmail, "john", "john", false},
+		{"user id number", AttrUserID, 42, "42", true},
+		{"anonymous string", AttrUserAnonymous, "true", true, true},
+		{"age float", AttrAge, 31.0, 31, true},
+		{"age string", AttrAge, "27", 27, true},
+		{"age out of range", AttrAge, 420, 420, false},
+		{"ip", AttrIP, "::ffff:10.0.0.1", "10.0.0.1", true},
+		{"net.IP", AttrIP, net.ParseIP("192.168.1.1"), "192.168.1.1", true},
+		{"ip invalid", AttrIP, "not-an-ip", "not-an-ip", false},
+		{"device type lower case", AttrDeviceType, "Mobile", "mobile", true},
+		{"os trimmed", AttrOS, " iOS ", "iOS", true},
+		{"time", "signup", time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600)), "2024-01-02T02:04:05Z", true},
+		{"custom untouched", "plan", "Pro", "Pro", true},
+		{"wrong type", AttrCity, 7, 7, false},
+		{"long string", "bio", strings.Repeat("a", MaxAttributeValueLength+10), strings.Repeat("a", MaxAttributeValueLength), false},
+		{"unencodable", "callback", func() {}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := RequestContext{tt.key: tt.value}
+
+			out, err := in.Normalize()
+
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidAttribute)
+
+				var attrErr *AttributeError
+				require.True(t, errors.As(err, &attrErr))
+				assert.Equal(t, tt.key, attrErr.Attribute)
+			}
+
+			require.Contains(t, out, tt.key, "attributes must never be dropped")
+			if tt.want != nil {
+				assert.Equal(t, tt.want, out[tt.key])
+			}
+
+			_, marshalErr := json.Marshal(out[tt.key])
+			assert.NoError(t, marshalErr)
+		})
+	}
+}
+
+func TestRequestContextNormalizeLimits(t *testing.T) {
+	long := strings.Repeat("k", MaxAttributeKeyLength+5)
+
+	in := RequestContext{long: "v", long + "x": "w"}
+	for i := range MaxAttributes {
+		in[fmt.Sprintf("z%03d", i)] = i
+	}
+
+	out, err := in.Normalize()
+	assert.ErrorIs(t, err, ErrInvalidAttribute)
+	assert.Len(t, out, MaxAttributes)
+	assert.Contains(t, out, "z000")
+	assert.NotContains(t, out, fmt.Sprintf("z%03d", MaxAttributes-1))
+	assert.Equal(t, "v", out[long[:MaxAttributeKeyLength]])
+	assert.NotContains(t, out, long)
+
+	out, err = normalizeAttributeSet(RequestContext{"a": 1, "b": 2}, map[string]any{"a": 0, "c": 3})
+	require.NoError(t, err)
+	assert.Len(t, out, 2)
+}
+
+func TestRequestContextNormalizeDoesNotModify(t *testing.T) {
+	in := NewContext().WithCountry("de")
+
+	_, err := in.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, "de", in[AttrCountryCode])
+}
+
+func TestClientAttributeValidation(t *testing.T) {
+	var calls atomic.Int32
+	var seen atomic.Value
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", capturingEvaluateHandler(&calls, &seen, true, "on"))
+
+	req := NewContext().WithCountry("de").WithLanguage("de_de").Set(AttrAge, "old")
+
+	off := newTestClient(t, mux)
+	res := off.Evaluate("f", req.Set("callback", func() {}))
+	require.NoError(t, res.Err())
+	assert.Equal(t, map[string]any{AttrCountryCode: "de", AttrLanguage: "de_de", AttrAge: "old"}, seen.Load())
+	delete(req, "callback")
+
+	lenient := newTestClient(t, mux, WithAttributeValidation(AttributeValidationLenient))
+	res = lenient.Evaluate("f", req)
+	require.NoError(t, res.Err())
+	assert.Equal(t, map[string]any{AttrCountryCode: "DE", AttrLanguage: "de-DE", AttrAge: "old"}, seen.Load())
+
+	strict := newTestClient(t, mux, WithAttributeValidation(AttributeValidationStrict))
+	calls.Store(0)
+	res = strict.Evaluate("f", req)
+	assert.ErrorIs(t, res.Err(), ErrInvalidAttribute)
+	assert.Equal(t, SourceError, res.Source())
+	assert.Zero(t, calls.Load())
+
+	res = strict.Evaluate("f", NewContext().WithCountry("de"))
+	require.NoError(t, res.Err())
+	assert.Equal(t, map[string]any{AttrCountryCode: "DE"}, seen.Load())
+}
+
+func TestClientAttributeValidationCountsDefaults(t *testing.T) {
+	var calls atomic.Int32
+	var seen atomic.Value
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", capturingEvaluateHandler(&calls, &seen, true, "on"))
+
+	defaults := map[string]any{AttrService: "checkout", AttrCountryCode: "de"}
+	req := NewContext()
+	for i := range MaxAttributes - 1 {
+		req[fmt.Sprintf("a%03d", i)] = i
+	}
+
+	lenient := newTestClient(t, mux,
+		WithAttributeValidation(AttributeValidationLenient),
+		WithDefaultAttributes(defaults),
+	)
+	res := lenient.Evaluate("f", req)
+	require.NoError(t, res.Err())
+
+	body := seen.Load().(map[string]any)
+	assert.Len(t, body, MaxAttributes)
+	assert.Equal(t, "DE", body[AttrCountryCode])
+	assert.NotContains(t, body, fmt.Sprintf("a%03d", MaxAttributes-2))
+
+	strict := newTestClient(t, mux,
+		WithAttributeValidation(AttributeValidationStrict),
+		WithDefaultAttributes(defaults),
+	)
+	res = strict.Evaluate("f", req)
+	assert.ErrorIs(t, res.Err(), ErrInvalidAttribute)
+
+	_, err := NewClient(DefaultConfig("key"),
+		WithAttributeValidation(AttributeValidationStrict),
+		WithDefaultAttributes(map[string]any{AttrCountryCode: "Germany"}),
+	)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.ErrorIs(t, err, ErrInvalidAttribute)
+}
+
+func TestClientFingerprintNormalizesAttributes(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "on"))
+
+	client := newTestClient(t, mux,
+		WithCache(10, time.Minute),
+		WithAttributeValidation(AttributeValidationLenient),
+	)
+
+	req := NewContext().WithCountry(" de ")
+	client.Evaluate("f", req)
+
+	entries := client.cache.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, entries[0].Fingerprint, client.Fingerprint("f", req))
+	assert.Equal(t, client.Fingerprint("f", NewContext().WithCountry("DE")), client.Fingerprint("f", req))
+}
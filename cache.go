@@ -6,10 +6,14 @@ import (
 )
 
 type CacheEntry struct {
-	Value   string
-	Enabled bool
-	Found   bool
-	Expires time.Time
+	FeatureKey  string
+	Fingerprint string
+	Value       string
+	Enabled     bool
+	Found       bool
+	StoredAt    time.Time
+	Expires     time.Time
+	Stale       bool
 }
 
 func (e *CacheEntry) IsExpired() bool {
@@ -34,8 +38,8 @@ func NewLRUCache(capacity int, ttl time.Duration) *LRUCache {
 }
 
 func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	entry, exists := c.items[key]
 	if !exists || entry.IsExpired() {
@@ -47,15 +51,35 @@ func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
 	return entry, true
 }
 
-func (c *LRUCache) Set(key string, value string, enabled, found bool) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// GetStale returns the entry for key even if it has already expired.
+func (c *LRUCache) GetStale(key string) (*CacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	entry := &CacheEntry{
+	entry, exists := c.items[key]
+
+	return entry, exists
+}
+
+func (c *LRUCache) Set(key string, value string, enabled, found bool) {
+	c.SetEntry(key, &CacheEntry{
 		Value:   value,
 		Enabled: enabled,
 		Found:   found,
-		Expires: time.Now().Add(c.ttl),
+	})
+}
+
+// SetEntry stores entry under key. Zero StoredAt and Expires are filled in
+// from the current time and the cache TTL.
+func (c *LRUCache) SetEntry(key string, entry *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry.StoredAt.IsZero() {
+		entry.StoredAt = time.Now()
+	}
+	if entry.Expires.IsZero() {
+		entry.Expires = entry.StoredAt.Add(c.ttl)
 	}
 
 	if _, exists := c.items[key]; exists {
@@ -73,6 +97,41 @@ func (c *LRUCache) Set(key string, value string, enabled, found bool) {
 	c.order = append(c.order, key)
 }
 
+// DeleteFeature removes all entries of featureKey and returns their number.
+func (c *LRUCache) DeleteFeature(featureKey string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	order := c.order[:0]
+
+	for _, key := range c.order {
+		if c.items[key].FeatureKey == featureKey {
+			delete(c.items, key)
+			removed++
+
+			continue
+		}
+		order = append(order, key)
+	}
+	c.order = order
+
+	return removed
+}
+
+// Entries returns a copy of all entries, including expired ones, in LRU order.
+func (c *LRUCache) Entries() []CacheEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entries := make([]CacheEntry, 0, len(c.order))
+	for _, key := range c.order {
+		entries = append(entries, *c.items[key])
+	}
+
+	return entries
+}
+
 func (c *LRUCache) moveToEnd(key string) {
 	for i, k := range c.order {
 		if k == key {
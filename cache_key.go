@@ -0,0 +1,117 @@
+package togglr
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/togglr-project/togglr-sdk-go/fingerprint"
+)
+
+// CacheKeyPolicy selects which request attributes participate in cache keys.
+//
+// For a given feature the attribute set is taken from PerFeature, then from
+// the attributes reported by the server (when ServerHints is set), then from
+// Include. An empty Include means all attributes, while an empty per-feature
+// or server-reported set means none. Exclude is always applied last.
+//
+// Config.DefaultAttributes are only considered when DefaultAttributes is set.
+type CacheKeyPolicy struct {
+	Include           []string
+	Exclude           []string
+	PerFeature        map[string][]string
+	ServerHints       bool
+	DefaultAttributes bool
+}
+
+func (p *CacheKeyPolicy) isZero() bool {
+	return len(p.Include) == 0 && len(p.Exclude) == 0 && len(p.PerFeature) == 0 &&
+		!p.ServerHints && !p.DefaultAttributes
+}
+
+type cacheKeyer struct {
+	policy CacheKeyPolicy
+
+	mu    sync.RWMutex
+	hints map[string][]string
+}
+
+func newCacheKeyer(policy CacheKeyPolicy) *cacheKeyer {
+	return &cacheKeyer{
+		policy: policy,
+		hints:  make(map[string][]string),
+	}
+}
+
+func (k *cacheKeyer) fingerprint(featureKey string, req RequestContext) string {
+	if k == nil {
+		return fingerprint.Fingerprint(req)
+	}
+
+	return fingerprint.Fingerprint(k.selectAttributes(featureKey, req))
+}
+
+func (k *cacheKeyer) selectAttributes(featureKey string, req RequestContext) map[string]any {
+	include, restricted := k.policy.PerFeature[featureKey]
+	if !restricted && k.policy.ServerHints {
+		k.mu.RLock()
+		include, restricted = k.hints[featureKey]
+		k.mu.RUnlock()
+	}
+	if !restricted {
+		include = k.policy.Include
+		restricted = len(include) > 0
+	}
+
+	selected := make(map[string]any, len(req))
+	if !restricted {
+		for attr, v := range req {
+			selected[attr] = v
+		}
+	} else {
+		for _, attr := range include {
+			if v, exists := req[attr]; exists {
+				selected[attr] = v
+			}
+		}
+	}
+
+	for _, attr := range k.policy.Exclude {
+		delete(selected, attr)
+	}
+
+	return selected
+}
+
+func (k *cacheKeyer) rememberHint(featureKey string, attrs []string) {
+	if k == nil || !k.policy.ServerHints || attrs == nil {
+		return
+	}
+
+	attrs = slices.Clone(attrs)
+	slices.Sort(attrs)
+
+	k.mu.Lock()
+	k.hints[featureKey] = attrs
+	k.mu.Unlock()
+}
+
+// Fingerprint returns the fingerprint the client uses in cache keys for
+// featureKey and req, after applying attribute validation, the PII policy and
+// the cache key policy.
+func (c *Client) Fingerprint(featureKey string, req RequestContext) string {
+	attrs, err := c.prepareAttributes(OperationEvaluate, featureKey, req)
+	if err != nil {
+		attrs = c.pii.apply(req)
+	}
+
+	return c.fingerprint(featureKey, attrs)
+}
+
+// fingerprint is Fingerprint for attributes that were already prepared.
+func (c *Client) fingerprint(featureKey string, req RequestContext) string {
+	if c.keyer != nil && c.keyer.policy.DefaultAttributes {
+		req = mergeAttributes(c.defaultAttrs, req)
+	}
+
+	return c.keyer.fingerprint(featureKey, req)
+}
@@ -0,0 +1,113 @@
+package togglr
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheKeyerSelectAttributes(t *testing.T) {
+	req := RequestContext{
+		AttrUserID:      "u1",
+		AttrCountryCode: "US",
+		AttrIP:          "10.0.0.1",
+		"request_id":    "r-1",
+	}
+
+	tests := []struct {
+		name    string
+		policy  CacheKeyPolicy
+		feature string
+		want    map[string]any
+	}{
+		{
+			name:   "all attributes by default",
+			policy: CacheKeyPolicy{},
+			want:   map[string]any(req),
+		},
+		{
+			name:   "global allowlist",
+			policy: CacheKeyPolicy{Include: []string{AttrCountryCode, AttrUserID}},
+			want:   map[string]any{AttrCountryCode: "US", AttrUserID: "u1"},
+		},
+		{
+			name:   "global denylist",
+			policy: CacheKeyPolicy{Exclude: []string{AttrIP, "request_id"}},
+			want:   map[string]any{AttrCountryCode: "US", AttrUserID: "u1"},
+		},
+		{
+			name: "per feature overrides allowlist",
+			policy: CacheKeyPolicy{
+				Include:    []string{AttrUserID},
+				PerFeature: map[string][]string{"geo": {AttrCountryCode}},
+			},
+			feature: "geo",
+			want:    map[string]any{AttrCountryCode: "US"},
+		},
+		{
+			name: "empty per feature set means no attributes",
+			policy: CacheKeyPolicy{
+				PerFeature: map[string][]string{"global": {}},
+			},
+			feature: "global",
+			want:    map[string]any{},
+		},
+		{
+			name: "denylist applies to per feature set",
+			policy: CacheKeyPolicy{
+				Exclude:    []string{AttrIP},
+				PerFeature: map[string][]string{"geo": {AttrCountryCode, AttrIP}},
+			},
+			feature: "geo",
+			want:    map[string]any{AttrCountryCode: "US"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newCacheKeyer(tt.policy)
+			assert.Equal(t, tt.want, k.selectAttributes(tt.feature, req))
+		})
+	}
+}
+
+func TestCacheKeyerServerHints(t *testing.T) {
+	k := newCacheKeyer(CacheKeyPolicy{ServerHints: true})
+
+	a := RequestContext{AttrCountryCode: "US", AttrIP: "10.0.0.1"}
+	b := RequestContext{AttrCountryCode: "US", AttrIP: "10.0.0.2"}
+	assert.NotEqual(t, k.fingerprint("geo", a), k.fingerprint("geo", b))
+
+	k.rememberHint("geo", []string{AttrCountryCode})
+	assert.Equal(t, k.fingerprint("geo", a), k.fingerprint("geo", b))
+
+	k.rememberHint("other", nil)
+	assert.NotEqual(t, k.fingerprint("other", a), k.fingerprint("other", b))
+}
+
+func TestClientCacheKeyServerHints(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusOK, map[string]any{
+			"feature_key": r.PathValue("feature_key"),
+			"enabled":     true,
+			"value":       "on",
+			"attributes":  []string{AttrCountryCode},
+		})
+	})
+
+	client := newTestClient(t, mux, WithCache(10, time.Minute), WithServerCacheKeyHints())
+
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		res := client.Evaluate("geo", NewContext().WithCountry("US").WithIP(ip))
+		require.NoError(t, res.Err())
+	}
+
+	assert.Equal(t, int32(1), calls.Load())
+}
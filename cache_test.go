@@ -70,3 +70,21 @@ func TestCacheSize(t *testing.T) {
 		t.Errorf("Expected size 3 after eviction, got %d", cache.Size())
 	}
 }
+
+func TestCacheDeleteFeature(t *testing.T) {
+	cache := NewLRUCache(10, time.Minute)
+	cache.SetEntry("a:1", &CacheEntry{FeatureKey: "a"})
+	cache.SetEntry("a:2", &CacheEntry{FeatureKey: "a"})
+	cache.SetEntry("b:1", &CacheEntry{FeatureKey: "b"})
+
+	if removed := cache.DeleteFeature("a"); removed != 2 {
+		t.Errorf("Expected 2 removed entries, got %d", removed)
+	}
+	if cache.Size() != 1 {
+		t.Errorf("Expected size 1, got %d", cache.Size())
+	}
+
+	if _, found := cache.Get("b:1"); !found {
+		t.Error("Expected b:1 to still be present")
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	api "github.com/togglr-project/togglr-sdk-go/internal/generated/client"
@@ -17,8 +19,34 @@ type Client struct {
 	httpClient *http.Client
 	apiClient  *api.Client
 	cache      *LRUCache
+	hot        *hotTracker
+	keyer      *cacheKeyer
+	pii        *piiProtector
 	logger     Logger
 	metrics    Metrics
+
+	streamClient *http.Client
+	stream       streamState
+	flagChanges  subscribers[FlagChange]
+	gens         featureGenerations
+
+	guard  *healthGuard
+	errAgg *errorAggregator
+
+	// defaultAttrs are Config.DefaultAttributes with the PII policy applied.
+	defaultAttrs map[string]any
+
+	bulkHealthUnsupported atomic.Bool
+	stats                 clientStats
+	debug                 *debugRecorder
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	// closeMu guards closed, so that no goroutine is added to wg once Close
+	// started waiting for it.
+	closeMu sync.Mutex
+	closed  bool
 }
 
 func NewClient(cfg *Config, opts ...Option) (*Client, error) {
@@ -30,6 +58,12 @@ func NewClient(cfg *Config, opts ...Option) (*Client, error) {
 		opt(cfg)
 	}
 
+	if cfg.PII != nil {
+		if err := cfg.PII.validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	if cfg.Logger == nil {
 		cfg.Logger = &NoOpLogger{}
 	}
@@ -71,9 +105,10 @@ func NewClient(cfg *Config, opts ...Option) (*Client, error) {
 
 	transport.TLSClientConfig = tlsConfig
 
+	// No client-wide timeout: every operation sets a context deadline, which
+	// per-call options may extend beyond Config.Timeout.
 	httpClient := &http.Client{
 		Transport: transport,
-		Timeout:   cfg.Timeout,
 	}
 
 	apiClient, err := api.NewClient(
@@ -90,14 +125,90 @@ func NewClient(cfg *Config, opts ...Option) (*Client, error) {
 		cache = NewLRUCache(cfg.CacheSize, cfg.CacheTTL)
 	}
 
-	return &Client{
+	client := &Client{
 		cfg:        cfg,
 		httpClient: httpClient,
 		apiClient:  apiClient,
 		cache:      cache,
 		logger:     cfg.Logger,
 		metrics:    cfg.Metrics,
-	}, nil
+		done:       make(chan struct{}),
+	}
+
+	if cfg.PII != nil {
+		client.pii = newPIIProtector(*cfg.PII)
+	}
+
+	defaults := cfg.DefaultAttributes
+	if cfg.AttributeValidation != AttributeValidationOff && len(defaults) > 0 {
+		normalized, err := RequestContext(defaults).Normalize()
+		if err != nil {
+			if cfg.AttributeValidation == AttributeValidationStrict {
+				return nil, fmt.Errorf("%w: default attributes: %w", ErrInvalidConfig, err)
+			}
+
+			client.logger.Warn("default attributes normalized", "error", err)
+		}
+		defaults = normalized
+	}
+	client.defaultAttrs = client.pii.apply(defaults)
+
+	if cfg.DebugHistory > 0 {
+		client.debug = newDebugRecorder(cfg.DebugHistory)
+	}
+
+	if !cfg.CacheKey.isZero() {
+		client.keyer = newCacheKeyer(cfg.CacheKey)
+	}
+
+	if cache != nil && cfg.SnapshotPath != "" {
+		if err := client.loadSnapshot(); err != nil {
+			client.logger.Warn("failed to load snapshot", "path", cfg.SnapshotPath, "error", err)
+		}
+
+		if cfg.SnapshotInterval > 0 {
+			client.wg.Add(1)
+			go client.runSnapshotLoop()
+		}
+	}
+
+	if cache != nil && cfg.Refresh.Interval > 0 {
+		client.hot = newHotTracker(cfg.CacheSize)
+		client.wg.Add(1)
+		go client.runRefreshLoop()
+	}
+
+	if cfg.HealthAware {
+		if cfg.HealthRefreshInterval <= 0 {
+			cfg.HealthRefreshInterval = DefaultConfig("").HealthRefreshInterval
+		}
+
+		client.guard = newHealthGuard()
+		client.wg.Add(1)
+		go client.runHealthGuardLoop()
+	}
+
+	if cfg.ErrorReporting != (ErrorReporting{}) {
+		client.errAgg = newErrorAggregator(cfg.ErrorReporting)
+
+		if cfg.ErrorReporting.Window > 0 {
+			client.wg.Add(1)
+			go client.runErrorAggregationLoop()
+		}
+	}
+
+	if cfg.StreamEnabled {
+		if cfg.StreamBackoff.BaseDelay <= 0 {
+			cfg.StreamBackoff = DefaultStreamBackoff()
+		}
+
+		// The stream is long-lived, so it must not inherit cfg.Timeout.
+		client.streamClient = &http.Client{Transport: transport}
+		client.wg.Add(1)
+		go client.runStreamLoop()
+	}
+
+	return client, nil
 }
 
 func NewClientWithDefaults(apiKey string, opts ...Option) (*Client, error) {
@@ -107,19 +218,91 @@ func NewClientWithDefaults(apiKey string, opts ...Option) (*Client, error) {
 }
 
 func (c *Client) Close() error {
-	if c.cache != nil {
-		c.cache.Clear()
+	var err error
+
+	c.closeOnce.Do(func() {
+		c.closeMu.Lock()
+		c.closed = true
+		close(c.done)
+		c.closeMu.Unlock()
+
+		c.wg.Wait()
+
+		if c.cache != nil {
+			err = c.SaveSnapshot()
+			c.cache.Clear()
+		}
+	})
+
+	return err
+}
+
+// goBackground runs fn in a goroutine that Close waits for. Once the client
+// is closed it returns false without running fn.
+func (c *Client) goBackground(fn func()) bool {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+
+	if c.closed {
+		return false
 	}
 
-	return nil
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		fn()
+	}()
+
+	return true
+}
+
+// doneContext returns a context that is canceled when the client is closed.
+func (c *Client) doneContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		select {
+		case <-c.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
 }
 
 func (c *Client) HealthCheck(ctx context.Context) error {
-	_, err := c.apiClient.SdkV1HealthGet(ctx)
+	err := c.healthCheck(ctx)
+	if err != nil {
+		c.stats.markFailure(err)
+	} else {
+		c.stats.markSuccess()
+	}
 
 	return err
 }
 
+func (c *Client) healthCheck(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	resp, err := c.apiClient.SdkV1HealthGet(ctx)
+	if err != nil {
+		return err
+	}
+
+	if r, ok := resp.(*api.SdkV1HealthGetDef); ok {
+		return &APIError{
+			Code:       "health_check_failed",
+			Message:    fmt.Sprintf("unexpected health check status: %d", r.StatusCode),
+			StatusCode: r.StatusCode,
+		}
+	}
+
+	return nil
+}
+
 type apiKeySecuritySource struct {
 	apiKey string
 }
@@ -0,0 +1,108 @@
+package togglr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.Handler, opts ...Option) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	cfg := DefaultConfig("test-api-key")
+	cfg.Backoff = Backoff{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1}
+
+	client, err := NewClient(cfg, append([]Option{WithBaseURL(srv.URL)}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func evaluateHandler(calls *atomic.Int32, enabled bool, value string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusOK, map[string]any{
+			"feature_key": r.PathValue("feature_key"),
+			"enabled":     enabled,
+			"value":       value,
+		})
+	}
+}
+
+// capturingEvaluateHandler is evaluateHandler that also stores the decoded
+// body of the last request in seen.
+func capturingEvaluateHandler(calls *atomic.Int32, seen *atomic.Value, enabled bool, value string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		seen.Store(body)
+		evaluateHandler(calls, enabled, value)(w, r)
+	}
+}
+
+type testMetrics struct {
+	NoOpMetrics
+	cacheHits    atomic.Int32
+	cacheMisses  atomic.Int32
+	autoDisabled atomic.Int32
+}
+
+func (m *testMetrics) IncCacheHit()                    { m.cacheHits.Add(1) }
+func (m *testMetrics) IncCacheMiss()                   { m.cacheMisses.Add(1) }
+func (m *testMetrics) IncFeatureAutoDisabled(_ string) { m.autoDisabled.Add(1) }
+
+func TestClientHealthCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sdk/v1/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"status":      "ok",
+			"server_time": time.Now().UTC().Format(time.RFC3339),
+		})
+	})
+
+	client := newTestClient(t, mux)
+
+	assert.NoError(t, client.HealthCheck(context.Background()))
+}
+
+func TestClientCloseIsIdempotent(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, client.Close())
+}
+
+func TestClientEvaluateCtx(t *testing.T) {
+	var calls atomic.Int32
+	var seen atomic.Value
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", capturingEvaluateHandler(&calls, &seen, true, "on"))
+
+	client := newTestClient(t, mux)
+
+	ctx := WithRequestContext(context.Background(), NewContext().WithIP("10.0.0.1"))
+	ctx = WithRequestContext(ctx, NewContext().WithUserID("u1"))
+
+	res := client.EvaluateCtx(ctx, "new_ui")
+	require.NoError(t, res.Err())
+	assert.True(t, res.Enabled())
+	assert.Equal(t, map[string]any{AttrIP: "10.0.0.1", AttrUserID: "u1"}, seen.Load())
+}
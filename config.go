@@ -20,6 +20,51 @@ type Config struct {
 	ClientCert   string
 	ClientKey    string
 	CACert       string
+
+	SnapshotPath     string
+	SnapshotInterval time.Duration
+	SnapshotKey      []byte
+	SnapshotMaxAge   time.Duration
+
+	Refresh  Refresh
+	CacheKey CacheKeyPolicy
+
+	StreamEnabled bool
+	StreamBackoff Backoff
+	// StreamIdleTimeout is how long the stream may stay silent, heartbeat
+	// comments included, before it is considered dead and reconnected. Zero
+	// disables it.
+	StreamIdleTimeout time.Duration
+
+	WatchInterval time.Duration
+	WatchJitter   time.Duration
+
+	Defaults map[string]DefaultValue
+
+	HealthAware           bool
+	HealthRefreshInterval time.Duration
+	HealthWatchThreshold  float64
+
+	ErrorReporting ErrorReporting
+
+	Readiness ReadinessPolicy
+	// ReadinessLiveCheck makes Status, and so every readiness probe, run a
+	// HealthCheck against the server.
+	ReadinessLiveCheck bool
+
+	DebugHistory int
+
+	Hooks []Hook
+
+	// DefaultAttributes are sent with every evaluation, track event and error
+	// report. Attributes of the request, event or report take precedence.
+	DefaultAttributes map[string]any
+
+	AttributeValidation AttributeValidation
+
+	// PII, when set, hashes, truncates or drops sensitive attributes before
+	// they leave the process.
+	PII *PIIPolicy
 }
 
 type Backoff struct {
@@ -36,6 +81,14 @@ func DefaultBackoff() Backoff {
 	}
 }
 
+func DefaultStreamBackoff() Backoff {
+	return Backoff{
+		BaseDelay: time.Second,
+		MaxDelay:  30 * time.Second,
+		Factor:    2.0,
+	}
+}
+
 func DefaultConfig(apiKey string) *Config {
 	return &Config{
 		BaseURL:      "http://localhost:8090",
@@ -47,5 +100,17 @@ func DefaultConfig(apiKey string) *Config {
 		CacheSize:    100,
 		CacheTTL:     5 * time.Second,
 		MaxConns:     100,
+
+		SnapshotInterval: 30 * time.Second,
+		SnapshotMaxAge:   24 * time.Hour,
+
+		StreamBackoff:     DefaultStreamBackoff(),
+		StreamIdleTimeout: 90 * time.Second,
+
+		WatchInterval: 10 * time.Second,
+		WatchJitter:   time.Second,
+
+		HealthRefreshInterval: 30 * time.Second,
+		HealthWatchThreshold:  0.8,
 	}
 }
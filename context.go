@@ -1,5 +1,17 @@
 package togglr
 
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"math"
+	"strconv"
+)
+
+// RequestContext holds the attributes of an evaluation. Its With* methods
+// and Set modify the context in place, so a context shared between
+// goroutines must not be modified: derive per-goroutine contexts with Clone,
+// Merge or Without, or build them with ContextBuilder.
 type RequestContext map[string]any
 
 func NewContext() RequestContext {
@@ -124,3 +136,181 @@ func (r RequestContext) Set(key string, value any) RequestContext {
 
 	return r
 }
+
+// Clone returns a copy of r. The copy is shallow: attribute values are not
+// copied.
+func (r RequestContext) Clone() RequestContext {
+	out := make(RequestContext, len(r))
+	maps.Copy(out, r)
+
+	return out
+}
+
+// Merge returns a new context with the attributes of r and other, those of
+// other taking precedence. Neither r nor other is modified.
+func (r RequestContext) Merge(other RequestContext) RequestContext {
+	out := make(RequestContext, len(r)+len(other))
+	maps.Copy(out, r)
+	maps.Copy(out, other)
+
+	return out
+}
+
+// Without returns a new context with the attributes of r except keys. r is
+// not modified.
+func (r RequestContext) Without(keys ...string) RequestContext {
+	out := r.Clone()
+	for _, key := range keys {
+		delete(out, key)
+	}
+
+	return out
+}
+
+func (r RequestContext) Get(key string) (any, bool) {
+	v, ok := r[key]
+
+	return v, ok
+}
+
+// GetString returns the attribute key if it is a string.
+func (r RequestContext) GetString(key string) (string, bool) {
+	s, ok := r[key].(string)
+
+	return s, ok
+}
+
+// GetBool returns the attribute key if it is a bool.
+func (r RequestContext) GetBool(key string) (bool, bool) {
+	b, ok := r[key].(bool)
+
+	return b, ok
+}
+
+// GetInt returns the attribute key if it is an integer, or a float or
+// json.Number with an integral value, as attributes decoded from JSON are.
+func (r RequestContext) GetInt(key string) (int, bool) {
+	n, ok := intValue(r[key])
+
+	return int(n), ok
+}
+
+// GetFloat returns the attribute key if it is a number.
+func (r RequestContext) GetFloat(key string) (float64, bool) {
+	return floatValue(r[key])
+}
+
+func intValue(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), uint64(v) <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case float32:
+		return int64(v), float32(int64(v)) == v
+	case float64:
+		return int64(v), float64(int64(v)) == v
+	case json.Number:
+		n, err := v.Int64()
+
+		return n, err == nil
+	}
+
+	return 0, false
+}
+
+// uintValue is intValue for unsigned integers: it accepts the full uint64
+// range and rejects negative values.
+func uintValue(v any) (uint64, bool) {
+	switch v := v.(type) {
+	case uint:
+		return uint64(v), true
+	case uint64:
+		return v, true
+	case float32:
+		return uintValue(float64(v))
+	case float64:
+		if v < 0 || v >= math.MaxUint64 || v != math.Trunc(v) {
+			return 0, false
+		}
+
+		return uint64(v), true
+	case json.Number:
+		n, err := strconv.ParseUint(string(v), 10, 64)
+
+		return n, err == nil
+	}
+
+	if n, ok := intValue(v); ok && n >= 0 {
+		return uint64(n), true
+	}
+
+	return 0, false
+}
+
+func floatValue(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+
+		return f, err == nil
+	}
+
+	if n, ok := intValue(v); ok {
+		return float64(n), true
+	}
+
+	if n, ok := uintValue(v); ok {
+		return float64(n), true
+	}
+
+	return 0, false
+}
+
+type requestContextKey struct{}
+
+// WithRequestContext returns a copy of ctx carrying the attributes of rc merged
+// over those already carried by ctx, so that each layer of a request (for
+// example middleware, authentication and business code) can add its own.
+// rc is copied and may be modified afterwards.
+func WithRequestContext(ctx context.Context, rc RequestContext) context.Context {
+	parent, _ := ctx.Value(requestContextKey{}).(RequestContext)
+
+	merged := make(RequestContext, len(parent)+len(rc))
+	maps.Copy(merged, parent)
+	maps.Copy(merged, rc)
+
+	return context.WithValue(ctx, requestContextKey{}, merged)
+}
+
+// RequestContextFrom returns a copy of the attributes accumulated in ctx by
+// WithRequestContext. It returns an empty context if there are none.
+func RequestContextFrom(ctx context.Context) RequestContext {
+	rc, _ := ctx.Value(requestContextKey{}).(RequestContext)
+
+	if rc == nil {
+		return NewContext()
+	}
+
+	return maps.Clone(rc)
+}
@@ -0,0 +1,126 @@
+package togglr
+
+import "maps"
+
+// ContextBuilder builds request contexts without mutation: every With* method
+// returns a new builder with its own copy of the attributes and leaves the
+// receiver unchanged, so a base builder can be shared between goroutines and
+// extended in each of them.
+//
+// The zero value is an empty builder.
+type ContextBuilder struct {
+	attrs RequestContext
+}
+
+func NewContextBuilder() ContextBuilder {
+	return ContextBuilder{}
+}
+
+// Builder returns a builder starting from the attributes of r. r is copied
+// and may be modified afterwards.
+func (r RequestContext) Builder() ContextBuilder {
+	return ContextBuilder{attrs: r.Clone()}
+}
+
+func (b ContextBuilder) Set(key string, value any) ContextBuilder {
+	attrs := make(RequestContext, len(b.attrs)+1)
+	maps.Copy(attrs, b.attrs)
+	attrs[key] = value
+
+	return ContextBuilder{attrs: attrs}
+}
+
+// Merge returns a builder with the attributes of rc added, those of rc taking
+// precedence.
+func (b ContextBuilder) Merge(rc RequestContext) ContextBuilder {
+	return ContextBuilder{attrs: b.attrs.Merge(rc)}
+}
+
+func (b ContextBuilder) Without(keys ...string) ContextBuilder {
+	return ContextBuilder{attrs: b.attrs.Without(keys...)}
+}
+
+func (b ContextBuilder) Get(key string) (any, bool) {
+	return b.attrs.Get(key)
+}
+
+// Build returns the attributes as a new RequestContext owned by the caller.
+func (b ContextBuilder) Build() RequestContext {
+	return b.attrs.Clone()
+}
+
+func (b ContextBuilder) WithUserID(id string) ContextBuilder {
+	return b.Set(AttrUserID, id)
+}
+
+func (b ContextBuilder) WithUserEmail(email string) ContextBuilder {
+	return b.Set(AttrUserEmail, email)
+}
+
+func (b ContextBuilder) WithAnonymous(flag bool) ContextBuilder {
+	return b.Set(AttrUserAnonymous, flag)
+}
+
+func (b ContextBuilder) WithCountry(code string) ContextBuilder {
+	return b.Set(AttrCountryCode, code)
+}
+
+func (b ContextBuilder) WithRegion(region string) ContextBuilder {
+	return b.Set(AttrRegion, region)
+}
+
+func (b ContextBuilder) WithCity(city string) ContextBuilder {
+	return b.Set(AttrCity, city)
+}
+
+func (b ContextBuilder) WithManufacturer(m string) ContextBuilder {
+	return b.Set(AttrManufacturer, m)
+}
+
+func (b ContextBuilder) WithDeviceType(t string) ContextBuilder {
+	return b.Set(AttrDeviceType, t)
+}
+
+func (b ContextBuilder) WithOS(os string) ContextBuilder {
+	return b.Set(AttrOS, os)
+}
+
+func (b ContextBuilder) WithOSVersion(v string) ContextBuilder {
+	return b.Set(AttrOSVersion, v)
+}
+
+func (b ContextBuilder) WithBrowser(browser string) ContextBuilder {
+	return b.Set(AttrBrowser, browser)
+}
+
+func (b ContextBuilder) WithBrowserVersion(v string) ContextBuilder {
+	return b.Set(AttrBrowserVersion, v)
+}
+
+func (b ContextBuilder) WithLanguage(lang string) ContextBuilder {
+	return b.Set(AttrLanguage, lang)
+}
+
+func (b ContextBuilder) WithConnectionType(ct string) ContextBuilder {
+	return b.Set(AttrConnectionType, ct)
+}
+
+func (b ContextBuilder) WithAge(age int) ContextBuilder {
+	return b.Set(AttrAge, age)
+}
+
+func (b ContextBuilder) WithGender(g string) ContextBuilder {
+	return b.Set(AttrGender, g)
+}
+
+func (b ContextBuilder) WithIP(ip string) ContextBuilder {
+	return b.Set(AttrIP, ip)
+}
+
+func (b ContextBuilder) WithAppVersion(ver string) ContextBuilder {
+	return b.Set(AttrAppVersion, ver)
+}
+
+func (b ContextBuilder) WithPlatform(p string) ContextBuilder {
+	return b.Set(AttrPlatform, p)
+}
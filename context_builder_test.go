@@ -0,0 +1,81 @@
+package togglr
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestContextBuilder(t *testing.T) {
+	base := NewContextBuilder().WithCountry("DE").WithPlatform("web")
+
+	a := base.WithUserID("a")
+	b := base.WithUserID("b").WithCountry("FR").Without(AttrPlatform)
+
+	if got := base.Build(); len(got) != 2 || got[AttrCountryCode] != "DE" {
+		t.Errorf("base builder changed: %v", got)
+	}
+
+	if got := a.Build(); got[AttrUserID] != "a" || got[AttrCountryCode] != "DE" || got[AttrPlatform] != "web" {
+		t.Errorf("unexpected context a: %v", got)
+	}
+
+	got := b.Build()
+	if got[AttrUserID] != "b" || got[AttrCountryCode] != "FR" {
+		t.Errorf("unexpected context b: %v", got)
+	}
+
+	if _, ok := got[AttrPlatform]; ok {
+		t.Errorf("Without did not remove the platform: %v", got)
+	}
+
+	if _, ok := b.Get(AttrPlatform); ok {
+		t.Error("Get should not return a removed attribute")
+	}
+
+	if v, ok := b.Get(AttrCountryCode); !ok || v != "FR" {
+		t.Errorf("Get = %v, %v", v, ok)
+	}
+}
+
+func TestContextBuilderBuildIsOwned(t *testing.T) {
+	rc := NewContext().WithUserID("u1")
+	builder := rc.Builder()
+
+	rc.WithUserID("changed")
+	built := builder.Build()
+	built.WithUserID("mutated")
+
+	if got := builder.Build()[AttrUserID]; got != "u1" {
+		t.Errorf("builder affected by mutations, user id = %v", got)
+	}
+
+	var zero ContextBuilder
+	if got := zero.Build(); got == nil || len(got) != 0 {
+		t.Errorf("zero builder built %v", got)
+	}
+}
+
+func TestContextBuilderConcurrentUse(t *testing.T) {
+	base := NewContextBuilder().WithCountry("DE").Set("tenant", "acme")
+
+	var wg sync.WaitGroup
+	for i := range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			id := fmt.Sprintf("u%d", i)
+			rc := base.WithUserID(id).WithAge(i).Build()
+
+			if rc[AttrUserID] != id || rc[AttrAge] != i || rc["tenant"] != "acme" || len(rc) != 4 {
+				t.Errorf("unexpected context: %v", rc)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := base.Build(); len(got) != 2 {
+		t.Errorf("base builder changed: %v", got)
+	}
+}
@@ -0,0 +1,344 @@
+package togglr
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const structTag = "togglr"
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+type structInfo struct {
+	fields []structField
+	err    error
+}
+
+// structInfos caches the fields of every struct type seen by
+// ContextFromStruct and Decode.
+var structInfos sync.Map // reflect.Type -> *structInfo
+
+func structInfoFor(t reflect.Type) *structInfo {
+	if info, ok := structInfos.Load(t); ok {
+		return info.(*structInfo)
+	}
+
+	info := &structInfo{}
+	info.fields, info.err = collectStructFields(t, "", nil, map[reflect.Type]bool{t: true})
+
+	if info.err == nil {
+		seen := make(map[string]bool, len(info.fields))
+		for _, f := range info.fields {
+			if seen[f.name] {
+				info.err = fmt.Errorf("%w: %s: attribute %q is set by more than one field", ErrInvalidStruct, t, f.name)
+
+				break
+			}
+			seen[f.name] = true
+		}
+	}
+
+	actual, _ := structInfos.LoadOrStore(t, info)
+
+	return actual.(*structInfo)
+}
+
+// collectStructFields returns the tagged fields of t. Tagged struct fields
+// prefix the names of their own fields with their tag and a dot; embedded
+// structs without a tag are flattened.
+func collectStructFields(
+	t reflect.Type,
+	prefix string,
+	index []int,
+	visiting map[reflect.Type]bool,
+) ([]structField, error) {
+	var fields []structField
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		// Exported fields of unexported embedded structs are promoted, as
+		// in encoding/json, unless the embedded field is a pointer, which
+		// Decode could not allocate.
+		embedded := f.Anonymous && f.Type.Kind() == reflect.Struct
+		if !f.IsExported() && !embedded {
+			continue
+		}
+
+		tag := f.Tag.Get(structTag)
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		idx := append(slices.Clone(index), i)
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct && !isTextType(ft) {
+			if name == "" && !f.Anonymous {
+				continue
+			}
+
+			if visiting[ft] {
+				return nil, fmt.Errorf("%w: %s refers to itself", ErrInvalidStruct, ft)
+			}
+
+			childPrefix := prefix
+			if name != "" {
+				childPrefix = prefix + name + "."
+			}
+
+			visiting[ft] = true
+			nested, err := collectStructFields(ft, childPrefix, idx, visiting)
+			delete(visiting, ft)
+
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+
+			continue
+		}
+
+		if name == "" || !f.IsExported() {
+			continue
+		}
+
+		fields = append(fields, structField{
+			name:      prefix + name,
+			index:     idx,
+			omitEmpty: slices.Contains(strings.Split(opts, ","), "omitempty"),
+		})
+	}
+
+	return fields, nil
+}
+
+func isTextType(t reflect.Type) bool {
+	return t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// ContextFromStruct builds a request context from the fields of the struct v
+// (or pointer to struct) tagged with `togglr:"name"`. Tag options:
+//
+//	UserID string    `togglr:"user.id"`
+//	Email  string    `togglr:"user.email,omitempty"` // skipped if empty
+//	Device Device    `togglr:"device"`                // fields become "device.<name>"
+//	Secret string    `togglr:"-"`                     // never read
+//
+// Embedded structs without a tag are flattened, nil pointers are skipped and
+// values implementing encoding.TextMarshaler (such as time.Time and net.IP)
+// are sent as text. Untagged fields are ignored. Field metadata is cached per
+// type.
+func ContextFromStruct(v any) (RequestContext, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("%w: nil %T", ErrInvalidStruct, v)
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T is not a struct", ErrInvalidStruct, v)
+	}
+
+	info := structInfoFor(rv.Type())
+	if info.err != nil {
+		return nil, info.err
+	}
+
+	rc := make(RequestContext, len(info.fields))
+
+	for _, f := range info.fields {
+		fv, err := rv.FieldByIndexErr(f.index)
+		if err != nil {
+			// A nil pointer to a nested or embedded struct.
+			continue
+		}
+
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		value, err := structFieldValue(fv)
+		if err != nil {
+			return nil, &AttributeError{Attribute: f.name, Value: fv.Interface(), Reason: err.Error()}
+		}
+		rc[f.name] = value
+	}
+
+	return rc, nil
+}
+
+// structFieldValue converts named basic types to their underlying type so
+// that typed getters and targeting see plain strings, bools and numbers.
+func structFieldValue(v reflect.Value) (any, error) {
+	if isTextType(v.Type()) {
+		if !v.CanAddr() {
+			p := reflect.New(v.Type())
+			p.Elem().Set(v)
+			v = p.Elem()
+		}
+
+		m, ok := v.Interface().(encoding.TextMarshaler)
+		if !ok {
+			m = v.Addr().Interface().(encoding.TextMarshaler)
+		}
+
+		text, err := m.MarshalText()
+
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// Decode stores the attributes of r in the tagged fields of the struct v
+// points to, the reverse of ContextFromStruct. Missing and nil attributes
+// leave their fields unchanged, nil pointers are allocated as needed, and
+// numbers are converted between numeric types when they fit, so contexts
+// decoded from JSON can be used. Attributes that cannot be stored are
+// returned as *AttributeError.
+func (r RequestContext) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: Decode requires a non-nil pointer to a struct, got %T", ErrInvalidStruct, v)
+	}
+	rv = rv.Elem()
+
+	info := structInfoFor(rv.Type())
+	if info.err != nil {
+		return info.err
+	}
+
+	var errs []error
+
+	for _, f := range info.fields {
+		raw, ok := r[f.name]
+		if !ok || raw == nil {
+			continue
+		}
+
+		fv := fieldForSet(rv, f.index)
+
+		target := fv
+		if fv.Kind() == reflect.Pointer {
+			target = reflect.New(fv.Type().Elem()).Elem()
+		}
+
+		if err := setStructField(target, raw); err != nil {
+			errs = append(errs, &AttributeError{Attribute: f.name, Value: raw, Reason: err.Error()})
+
+			continue
+		}
+
+		if fv.Kind() == reflect.Pointer {
+			fv.Set(target.Addr())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fieldForSet returns the field at index, allocating nil struct pointers on
+// the way.
+func fieldForSet(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+func setStructField(dst reflect.Value, raw any) error {
+	rv := reflect.ValueOf(raw)
+	if rv.Type().AssignableTo(dst.Type()) {
+		dst.Set(rv)
+
+		return nil
+	}
+
+	if s, ok := raw.(string); ok && dst.Addr().Type().Implements(textUnmarshalerType) {
+		return dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		if s, ok := raw.(string); ok {
+			dst.SetString(s)
+
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := raw.(bool); ok {
+			dst.SetBool(b)
+
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := intValue(raw); ok && !dst.OverflowInt(n) {
+			dst.SetInt(n)
+
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := uintValue(raw); ok && !dst.OverflowUint(n) {
+			dst.SetUint(n)
+
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, ok := floatValue(raw); ok && !dst.OverflowFloat(f) {
+			dst.SetFloat(f)
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot decode %T into %s", raw, dst.Type())
+}
@@ -0,0 +1,186 @@
+package togglr
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPlan string
+
+type testDevice struct {
+	Type string `togglr:"type"`
+	OS   string `togglr:"os,omitempty"`
+}
+
+type testAccount struct {
+	Plan    testPlan  `togglr:"plan"`
+	Created time.Time `togglr:"created_at,omitempty"`
+}
+
+type testUser struct {
+	Audit
+	testSession
+
+	Account *testAccount `togglr:"account"`
+
+	ID       string      `togglr:"user.id"`
+	Email    string      `togglr:"user.email,omitempty"`
+	Age      *int        `togglr:"age"`
+	Beta     bool        `togglr:"beta"`
+	IP       net.IP      `togglr:"ip,omitempty"`
+	Device   testDevice  `togglr:"device"`
+	Backup   *testDevice `togglr:"backup"`
+	Password string      `togglr:"-"`
+	Untagged string
+}
+
+type Audit struct {
+	Region string `togglr:"region"`
+}
+
+type testSession struct {
+	SessionID string `togglr:"session.id,omitempty"`
+}
+
+func TestContextFromStruct(t *testing.T) {
+	age := 30
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	u := testUser{
+		Account:     &testAccount{Plan: "pro", Created: created},
+		Audit:       Audit{Region: "eu"},
+		testSession: testSession{SessionID: "s1"},
+		ID:          "u1",
+		Age:         &age,
+		IP:          net.ParseIP("203.0.113.7"),
+		Device:      testDevice{Type: "mobile"},
+		Password:    "secret",
+		Untagged:    "x",
+	}
+
+	rc, err := ContextFromStruct(&u)
+	require.NoError(t, err)
+
+	assert.Equal(t, RequestContext{
+		"account.plan":       "pro",
+		"account.created_at": "2024-05-01T12:00:00Z",
+		AttrRegion:           "eu",
+		"session.id":         "s1",
+		AttrUserID:           "u1",
+		AttrAge:              30,
+		"beta":               false,
+		AttrIP:               "203.0.113.7",
+		"device.type":        "mobile",
+	}, rc)
+
+	u.Account = nil
+	u.Age = nil
+	rc, err = ContextFromStruct(u)
+	require.NoError(t, err)
+	assert.NotContains(t, rc, "account.plan")
+	assert.NotContains(t, rc, AttrAge)
+}
+
+func TestContextFromStructInvalid(t *testing.T) {
+	type duplicate struct {
+		A string `togglr:"user.id"`
+		B string `togglr:"user.id"`
+	}
+
+	type node struct {
+		Name   string `togglr:"name"`
+		Parent *node  `togglr:"parent"`
+	}
+
+	for name, v := range map[string]any{
+		"not a struct": "user",
+		"nil pointer":  (*testUser)(nil),
+		"duplicate":    duplicate{},
+		"cycle":        node{},
+	} {
+		_, err := ContextFromStruct(v)
+		assert.True(t, errors.Is(err, ErrInvalidStruct), "%s: %v", name, err)
+	}
+}
+
+func TestContextFromStructCachesMetadata(t *testing.T) {
+	_, err := ContextFromStruct(testDevice{})
+	require.NoError(t, err)
+
+	info, ok := structInfos.Load(reflect.TypeFor[testDevice]())
+	require.True(t, ok)
+	assert.Same(t, info, structInfoFor(reflect.TypeFor[testDevice]()))
+}
+
+func TestRequestContextDecode(t *testing.T) {
+	var rc RequestContext
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"user.id": "u1",
+		"age": 30,
+		"beta": true,
+		"ip": "203.0.113.7",
+		"region": "eu",
+		"session.id": "s1",
+		"account.plan": "pro",
+		"account.created_at": "2024-05-01T12:00:00Z",
+		"backup.type": "tablet",
+		"unknown": 1
+	}`), &rc))
+
+	var u testUser
+	require.NoError(t, rc.Decode(&u))
+
+	assert.Equal(t, "u1", u.ID)
+	require.NotNil(t, u.Age)
+	assert.Equal(t, 30, *u.Age)
+	assert.True(t, u.Beta)
+	assert.Equal(t, "203.0.113.7", u.IP.String())
+	assert.Equal(t, "eu", u.Region)
+	assert.Equal(t, "s1", u.SessionID)
+	require.NotNil(t, u.Account)
+	assert.Equal(t, testPlan("pro"), u.Account.Plan)
+	assert.True(t, u.Account.Created.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)))
+	require.NotNil(t, u.Backup)
+	assert.Equal(t, "tablet", u.Backup.Type)
+
+	back, err := ContextFromStruct(u)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", back[AttrUserID])
+	assert.Equal(t, 30, back[AttrAge])
+}
+
+func TestRequestContextDecodeLargeUnsigned(t *testing.T) {
+	type counters struct {
+		Total uint64 `togglr:"total"`
+	}
+
+	in := counters{Total: math.MaxUint64}
+	rc, err := ContextFromStruct(in)
+	require.NoError(t, err)
+
+	var out counters
+	require.NoError(t, rc.Decode(&out))
+	assert.Equal(t, in, out)
+}
+
+func TestRequestContextDecodeErrors(t *testing.T) {
+	var u testUser
+
+	err := RequestContext{AttrAge: 2.5, "beta": "yes", AttrUserID: "u1"}.Decode(&u)
+
+	var attrErr *AttributeError
+	require.ErrorAs(t, err, &attrErr)
+	assert.True(t, errors.Is(err, ErrInvalidAttribute))
+	assert.Nil(t, u.Age)
+	assert.Equal(t, "u1", u.ID)
+
+	assert.True(t, errors.Is(NewContext().Decode(u), ErrInvalidStruct))
+}
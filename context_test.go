@@ -1,6 +1,11 @@
 package togglr
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
 	"testing"
 )
 
@@ -520,3 +525,150 @@ func TestRequestContext_MultipleContexts(t *testing.T) {
 		t.Errorf("Expected ctx2 user ID 'user2', got %v", ctx2[AttrUserID])
 	}
 }
+
+func TestWithRequestContextMerges(t *testing.T) {
+	ctx := WithRequestContext(context.Background(), NewContext().WithIP("10.0.0.1").WithCountry("DE"))
+
+	auth := NewContext().WithUserID("u1")
+	ctx = WithRequestContext(ctx, auth)
+	inner := WithRequestContext(ctx, NewContext().Set("tenant", "acme").WithCountry("FR"))
+
+	auth.WithUserID("changed")
+
+	rc := RequestContextFrom(inner)
+	want := map[string]any{
+		AttrIP:          "10.0.0.1",
+		AttrCountryCode: "FR",
+		AttrUserID:      "u1",
+		"tenant":        "acme",
+	}
+	if len(rc) != len(want) {
+		t.Fatalf("Expected %d attributes, got %v", len(want), rc)
+	}
+	for k, v := range want {
+		if rc[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, rc[k])
+		}
+	}
+
+	outer := RequestContextFrom(ctx)
+	if outer[AttrCountryCode] != "DE" || outer["tenant"] != nil {
+		t.Errorf("Inner layers must not change outer ones, got %v", outer)
+	}
+
+	rc.Set("tenant", "other")
+	if RequestContextFrom(inner)["tenant"] != "acme" {
+		t.Error("RequestContextFrom should return a copy")
+	}
+}
+
+func TestRequestContextFromEmpty(t *testing.T) {
+	rc := RequestContextFrom(context.Background())
+
+	if rc == nil || len(rc) != 0 {
+		t.Errorf("Expected empty context, got %v", rc)
+	}
+}
+
+func TestRequestContextDerivation(t *testing.T) {
+	base := NewContext().WithUserID("u1").WithCountry("DE")
+
+	clone := base.Clone().WithUserID("u2")
+	merged := base.Merge(RequestContext{AttrCountryCode: "FR", AttrCity: "Paris"})
+	without := base.Without(AttrCountryCode, "missing")
+
+	if base[AttrUserID] != "u1" || base[AttrCountryCode] != "DE" || len(base) != 2 {
+		t.Fatalf("base context was modified: %v", base)
+	}
+
+	if clone[AttrUserID] != "u2" || clone[AttrCountryCode] != "DE" {
+		t.Errorf("unexpected clone: %v", clone)
+	}
+
+	if merged[AttrUserID] != "u1" || merged[AttrCountryCode] != "FR" || merged[AttrCity] != "Paris" {
+		t.Errorf("unexpected merge: %v", merged)
+	}
+
+	if _, ok := without.Get(AttrCountryCode); ok || without[AttrUserID] != "u1" {
+		t.Errorf("unexpected without: %v", without)
+	}
+}
+
+func TestRequestContextTypedGetters(t *testing.T) {
+	rc := RequestContext{
+		"s":   "text",
+		"b":   true,
+		"i":   int64(42),
+		"f":   2.5,
+		"fi":  3.0,
+		"num": json.Number("7"),
+	}
+
+	if s, ok := rc.GetString("s"); !ok || s != "text" {
+		t.Errorf("GetString = %q, %v", s, ok)
+	}
+
+	if _, ok := rc.GetString("b"); ok {
+		t.Error("GetString should not convert a bool")
+	}
+
+	if b, ok := rc.GetBool("b"); !ok || !b {
+		t.Errorf("GetBool = %v, %v", b, ok)
+	}
+
+	for key, want := range map[string]int{"i": 42, "fi": 3, "num": 7} {
+		if n, ok := rc.GetInt(key); !ok || n != want {
+			t.Errorf("GetInt(%q) = %d, %v", key, n, ok)
+		}
+	}
+
+	if _, ok := rc.GetInt("f"); ok {
+		t.Error("GetInt should reject a fractional float")
+	}
+
+	if f, ok := rc.GetFloat("i"); !ok || f != 42 {
+		t.Errorf("GetFloat = %v, %v", f, ok)
+	}
+
+	if _, ok := rc.GetFloat("missing"); ok {
+		t.Error("GetFloat should report a missing attribute")
+	}
+
+	big := RequestContext{"big": uint64(math.MaxUint64)}
+	if n, ok := big.GetInt("big"); ok {
+		t.Errorf("GetInt should reject an overflowing uint64, got %d", n)
+	}
+
+	if f, ok := big.GetFloat("big"); !ok || f != math.MaxUint64 {
+		t.Errorf("GetFloat(big) = %v, %v", f, ok)
+	}
+}
+
+func TestRequestContextConcurrentDerivation(t *testing.T) {
+	base := NewContext().WithCountry("DE").WithPlatform("web")
+
+	var wg sync.WaitGroup
+	results := make([]RequestContext, 32)
+
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			rc := base.Merge(NewContext().WithUserID(fmt.Sprintf("u%d", i)))
+			_ = rc.Without(AttrPlatform)
+			results[i] = rc
+		}()
+	}
+	wg.Wait()
+
+	for i, rc := range results {
+		if rc[AttrUserID] != fmt.Sprintf("u%d", i) || rc[AttrCountryCode] != "DE" {
+			t.Errorf("unexpected context %d: %v", i, rc)
+		}
+	}
+
+	if len(base) != 2 {
+		t.Errorf("base context was modified: %v", base)
+	}
+}
@@ -0,0 +1,386 @@
+package togglr
+
+import (
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed debug.html
+var debugPage string
+
+var debugTemplate = template.Must(template.New("debug").Parse(debugPage))
+
+type debugEvaluation struct {
+	Time       time.Time      `json:"time"`
+	FeatureKey string         `json:"feature_key"`
+	Context    RequestContext `json:"context"`
+	Enabled    bool           `json:"enabled"`
+	Value      string         `json:"value"`
+	Found      bool           `json:"found"`
+	Source     EvalSource     `json:"source"`
+	Error      string         `json:"error,omitempty"`
+	Duration   time.Duration  `json:"duration_ns"`
+}
+
+type debugError struct {
+	Time       time.Time `json:"time"`
+	Operation  string    `json:"operation"`
+	FeatureKey string    `json:"feature_key,omitempty"`
+	Error      string    `json:"error"`
+}
+
+type ring[T any] struct {
+	mu    sync.Mutex
+	items []T
+	next  int
+	full  bool
+}
+
+func newRing[T any](size int) *ring[T] {
+	return &ring[T]{items: make([]T, size)}
+}
+
+func (r *ring[T]) add(v T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items[r.next] = v
+	r.next = (r.next + 1) % len(r.items)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// list returns the items newest first.
+func (r *ring[T]) list() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.items)
+	}
+
+	out := make([]T, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, r.items[(r.next-i+len(r.items))%len(r.items)])
+	}
+
+	return out
+}
+
+// debugRecorder keeps the recent evaluations and errors shown by
+// DebugHandler. A nil recorder records nothing.
+type debugRecorder struct {
+	evaluations *ring[debugEvaluation]
+	errors      *ring[debugError]
+}
+
+func newDebugRecorder(size int) *debugRecorder {
+	return &debugRecorder{
+		evaluations: newRing[debugEvaluation](size),
+		errors:      newRing[debugError](size),
+	}
+}
+
+func (d *debugRecorder) recordEvaluation(featureKey string, req RequestContext, res EvalResult, took time.Duration) {
+	if d == nil {
+		return
+	}
+
+	rec := debugEvaluation{
+		Time:       time.Now(),
+		FeatureKey: featureKey,
+		Context:    make(RequestContext, len(req)),
+		Enabled:    res.enabled,
+		Value:      res.rawValue,
+		Found:      res.found,
+		Source:     res.source,
+		Duration:   took,
+	}
+	for k, v := range req {
+		rec.Context[k] = v
+	}
+	if res.err != nil {
+		rec.Error = res.err.Error()
+	}
+
+	d.evaluations.add(rec)
+}
+
+func (d *debugRecorder) recordError(operation, featureKey string, err error) {
+	if d == nil || err == nil {
+		return
+	}
+
+	d.errors.add(debugError{
+		Time:       time.Now(),
+		Operation:  operation,
+		FeatureKey: featureKey,
+		Error:      err.Error(),
+	})
+}
+
+type DebugOption func(*debugOptions)
+
+type debugOptions struct {
+	authorize func(*http.Request) bool
+	basicAuth bool
+	noAuth    bool
+}
+
+// WithDebugAuth only serves requests for which authorize returns true.
+func WithDebugAuth(authorize func(*http.Request) bool) DebugOption {
+	return func(o *debugOptions) {
+		o.authorize = authorize
+	}
+}
+
+// WithDebugBasicAuth protects the debug handler with HTTP basic auth.
+func WithDebugBasicAuth(username, password string) DebugOption {
+	return func(o *debugOptions) {
+		o.basicAuth = true
+		o.authorize = func(r *http.Request) bool {
+			u, p, ok := r.BasicAuth()
+
+			return ok &&
+				subtle.ConstantTimeCompare([]byte(u), []byte(username)) == 1 &&
+				subtle.ConstantTimeCompare([]byte(p), []byte(password)) == 1
+		}
+	}
+}
+
+// WithDebugNoAuth serves the debug handler without authentication. Only use it
+// when the handler is reachable from trusted networks.
+func WithDebugNoAuth() DebugOption {
+	return func(o *debugOptions) {
+		o.noAuth = true
+	}
+}
+
+type debugCacheEntry struct {
+	FeatureKey  string    `json:"feature_key"`
+	Fingerprint string    `json:"fingerprint"`
+	Enabled     bool      `json:"enabled"`
+	Value       string    `json:"value"`
+	Found       bool      `json:"found"`
+	Expires     time.Time `json:"expires"`
+	Stale       bool      `json:"stale"`
+}
+
+type debugView struct {
+	Config      map[string]any    `json:"config"`
+	Cache       []debugCacheEntry `json:"cache"`
+	Evaluations []debugEvaluation `json:"evaluations"`
+	Errors      []debugError      `json:"errors"`
+	Result      *debugEvaluation  `json:"result,omitempty"`
+	FormError   string            `json:"form_error,omitempty"`
+	FormKey     string            `json:"-"`
+	FormContext string            `json:"-"`
+	History     bool              `json:"-"`
+}
+
+// DebugHandler returns an http.Handler that shows the redacted configuration,
+// cache entries, recent evaluations and errors, and evaluates a feature for an
+// ad-hoc RequestContext posted from its form. Recent evaluations and errors
+// are only kept when Config.DebugHistory is positive. Responses are JSON if
+// the request asks for application/json or has format=json.
+//
+// The handler exposes evaluation contexts and refuses every request unless it
+// is protected with WithDebugAuth or WithDebugBasicAuth, or WithDebugNoAuth is
+// given. Cross-origin POST requests are rejected.
+func (c *Client) DebugHandler(opts ...DebugOption) http.Handler {
+	var o debugOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.authorize == nil && !o.noAuth {
+		c.logger.Warn("debug handler has no authentication configured, refusing all requests")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if o.authorize == nil && !o.noAuth {
+			http.Error(w, "debug handler requires WithDebugAuth, WithDebugBasicAuth or WithDebugNoAuth", http.StatusForbidden)
+
+			return
+		}
+
+		if o.authorize != nil && !o.authorize(r) {
+			if o.basicAuth {
+				w.Header().Set("WWW-Authenticate", `Basic realm="togglr"`)
+			}
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+			return
+		}
+
+		view := debugView{FormContext: "{}", History: c.debug != nil}
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPost:
+			if !sameOrigin(r) {
+				http.Error(w, "cross-origin request rejected", http.StatusForbidden)
+
+				return
+			}
+			c.debugEvaluate(r, &view)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		view.Config = c.redactedConfig()
+		view.Cache = c.debugCacheEntries()
+		if c.debug != nil {
+			view.Evaluations = c.debug.evaluations.list()
+			view.Errors = c.debug.errors.list()
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+
+		if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(view)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := debugTemplate.Execute(w, view); err != nil {
+			c.logger.Warn("failed to render debug page", "error", err)
+		}
+	})
+}
+
+// sameOrigin reports whether r was not sent by a page from another origin.
+// Browsers set Sec-Fetch-Site or Origin on cross-origin form posts; requests
+// without either header come from non-browser clients.
+func sameOrigin(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "same-origin", "none":
+		return true
+	case "":
+	default:
+		return false
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+func (c *Client) debugEvaluate(r *http.Request, view *debugView) {
+	view.FormKey = strings.TrimSpace(r.FormValue("feature_key"))
+	if raw := strings.TrimSpace(r.FormValue("context")); raw != "" {
+		view.FormContext = raw
+	}
+
+	if view.FormKey == "" {
+		view.FormError = "feature key is required"
+
+		return
+	}
+
+	req := NewContext()
+	dec := json.NewDecoder(strings.NewReader(view.FormContext))
+	dec.UseNumber()
+	if err := dec.Decode(&req); err != nil {
+		view.FormError = "invalid context JSON: " + err.Error()
+
+		return
+	}
+
+	start := time.Now()
+	res := c.EvaluateWithContext(r.Context(), view.FormKey, req)
+
+	view.Result = &debugEvaluation{
+		Time:       start,
+		FeatureKey: view.FormKey,
+		Context:    req,
+		Enabled:    res.enabled,
+		Value:      res.rawValue,
+		Found:      res.found,
+		Source:     res.source,
+		Duration:   time.Since(start),
+	}
+	if res.err != nil {
+		view.Result.Error = res.err.Error()
+	}
+}
+
+func (c *Client) debugCacheEntries() []debugCacheEntry {
+	if c.cache == nil {
+		return nil
+	}
+
+	entries := c.cache.Entries()
+	out := make([]debugCacheEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, debugCacheEntry{
+			FeatureKey:  e.FeatureKey,
+			Fingerprint: e.Fingerprint,
+			Enabled:     e.Enabled,
+			Value:       e.Value,
+			Found:       e.Found,
+			Expires:     e.Expires,
+			Stale:       e.Stale,
+		})
+	}
+
+	return out
+}
+
+// redactedConfig returns the configuration without secrets.
+func (c *Client) redactedConfig() map[string]any {
+	cfg := c.cfg
+
+	return map[string]any{
+		"base_url":            cfg.BaseURL,
+		"api_key":             redactSecret(cfg.APIKey),
+		"timeout":             cfg.Timeout.String(),
+		"retries":             cfg.Retries,
+		"cache_enabled":       cfg.CacheEnabled,
+		"cache_size":          cfg.CacheSize,
+		"cache_ttl":           cfg.CacheTTL.String(),
+		"insecure":            cfg.Insecure,
+		"client_cert":         cfg.ClientCert,
+		"ca_cert":             cfg.CACert,
+		"snapshot_path":       cfg.SnapshotPath,
+		"snapshot_encrypted":  len(cfg.SnapshotKey) > 0,
+		"refresh_interval":    cfg.Refresh.Interval.String(),
+		"stream_enabled":      cfg.StreamEnabled,
+		"health_aware":        cfg.HealthAware,
+		"defaults":            len(cfg.Defaults),
+		"error_report_window": cfg.ErrorReporting.Window.String(),
+		"pii_policy":          cfg.PII != nil,
+	}
+}
+
+func redactSecret(s string) string {
+	if len(s) <= 8 {
+		return strings.Repeat("*", len(s))
+	}
+
+	return s[:4] + strings.Repeat("*", len(s)-4)
+}
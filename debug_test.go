@@ -0,0 +1,161 @@
+package togglr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing(t *testing.T) {
+	r := newRing[int](3)
+	assert.Empty(t, r.list())
+
+	r.add(1)
+	r.add(2)
+	assert.Equal(t, []int{2, 1}, r.list())
+
+	r.add(3)
+	r.add(4)
+	assert.Equal(t, []int{4, 3, 2}, r.list())
+}
+
+func TestClientDebugHandler(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("feature_key") == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+		evaluateHandler(&calls, true, "blue")(w, r)
+	})
+
+	client := newTestClient(t, mux,
+		WithCache(10, time.Minute),
+		WithDebugHistory(10),
+		WithRetries(0),
+	)
+
+	client.Evaluate("new_ui", NewContext().WithUserID("u1"))
+	client.Evaluate("broken", NewContext())
+
+	w := httptest.NewRecorder()
+	client.DebugHandler(WithDebugNoAuth()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/togglr?format=json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var view struct {
+		Config      map[string]any    `json:"config"`
+		Cache       []debugCacheEntry `json:"cache"`
+		Evaluations []debugEvaluation `json:"evaluations"`
+		Errors      []debugError      `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&view))
+
+	assert.Equal(t, "test********", view.Config["api_key"])
+	require.Len(t, view.Cache, 1)
+	assert.Equal(t, "new_ui", view.Cache[0].FeatureKey)
+	assert.Equal(t, client.Fingerprint("new_ui", NewContext().WithUserID("u1")), view.Cache[0].Fingerprint)
+
+	require.Len(t, view.Evaluations, 2)
+	assert.Equal(t, "broken", view.Evaluations[0].FeatureKey)
+	assert.NotEmpty(t, view.Evaluations[0].Error)
+	assert.Equal(t, "u1", view.Evaluations[1].Context[AttrUserID])
+	assert.Equal(t, SourceServer, view.Evaluations[1].Source)
+
+	require.Len(t, view.Errors, 1)
+	assert.Equal(t, "evaluate", view.Errors[0].Operation)
+}
+
+func TestClientDebugHandlerEvaluateForm(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "<b>blue</b>"))
+
+	client := newTestClient(t, mux)
+
+	form := url.Values{"feature_key": {"new_ui"}, "context": {`{"user.id": "u1"}`}}
+	r := httptest.NewRequest(http.MethodPost, "/debug/togglr", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	client.DebugHandler(WithDebugNoAuth()).ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "&lt;b&gt;blue&lt;/b&gt;")
+	assert.Contains(t, w.Body.String(), "History is disabled")
+	assert.Equal(t, int32(1), calls.Load())
+
+	form.Set("context", "{not json")
+	r = httptest.NewRequest(http.MethodPost, "/debug/togglr", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w = httptest.NewRecorder()
+	client.DebugHandler(WithDebugNoAuth()).ServeHTTP(w, r)
+	assert.Contains(t, w.Body.String(), "invalid context JSON")
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClientDebugHandlerAuth(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+	h := client.DebugHandler(WithDebugBasicAuth("admin", "s3cret"))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/togglr", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+	r := httptest.NewRequest(http.MethodGet, "/debug/togglr", nil)
+	r.SetBasicAuth("admin", "s3cret")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestClientDebugHandlerRequiresAuthOption(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+
+	w := httptest.NewRecorder()
+	client.DebugHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/togglr", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "WithDebugNoAuth")
+}
+
+func TestClientDebugHandlerRejectsCrossOriginPost(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "blue"))
+
+	client := newTestClient(t, mux)
+	h := client.DebugHandler(WithDebugNoAuth())
+
+	post := func(header, value string) int {
+		form := url.Values{"feature_key": {"new_ui"}}
+		r := httptest.NewRequest(http.MethodPost, "http://debug.local/debug/togglr", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set(header, value)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, post("Origin", "https://evil.example"))
+	assert.Equal(t, http.StatusForbidden, post("Sec-Fetch-Site", "cross-site"))
+	assert.Equal(t, int32(0), calls.Load())
+
+	assert.Equal(t, http.StatusOK, post("Origin", "http://debug.local"))
+	assert.Equal(t, http.StatusOK, post("Sec-Fetch-Site", "same-origin"))
+	assert.Equal(t, int32(2), calls.Load())
+}
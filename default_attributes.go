@@ -0,0 +1,71 @@
+package togglr
+
+import (
+	"maps"
+	"os"
+	"runtime/debug"
+	"sync"
+)
+
+const sdkModulePath = "github.com/togglr-project/togglr-sdk-go"
+
+var sdkVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if info.Main.Path == sdkModulePath {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == sdkModulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+
+			return dep.Version
+		}
+	}
+
+	return "unknown"
+})
+
+// StandardAttributes returns the attributes identifying the calling service,
+// meant for Config.DefaultAttributes: service, environment, app version, the
+// host name and the SDK version. Empty values are left out.
+func StandardAttributes(service, environment, appVersion string) map[string]any {
+	attrs := map[string]any{
+		AttrSDKVersion: sdkVersion(),
+	}
+
+	if service != "" {
+		attrs[AttrService] = service
+	}
+	if environment != "" {
+		attrs[AttrEnvironment] = environment
+	}
+	if appVersion != "" {
+		attrs[AttrAppVersion] = appVersion
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		attrs[AttrHostname] = hostname
+	}
+
+	return attrs
+}
+
+// mergeAttributes returns attrs layered over defaults. attrs is returned as
+// is when there are no defaults.
+func mergeAttributes(defaults, attrs map[string]any) map[string]any {
+	if len(defaults) == 0 {
+		return attrs
+	}
+
+	merged := make(map[string]any, len(defaults)+len(attrs))
+	maps.Copy(merged, defaults)
+	maps.Copy(merged, attrs)
+
+	return merged
+}
@@ -0,0 +1,66 @@
+package togglr
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandardAttributes(t *testing.T) {
+	attrs := StandardAttributes("checkout", "prod", "")
+
+	assert.Equal(t, "checkout", attrs[AttrService])
+	assert.Equal(t, "prod", attrs[AttrEnvironment])
+	assert.NotContains(t, attrs, AttrAppVersion)
+	assert.NotEmpty(t, attrs[AttrHostname])
+	assert.NotEmpty(t, attrs[AttrSDKVersion])
+}
+
+func TestClientDefaultAttributes(t *testing.T) {
+	var calls atomic.Int32
+	var evaluated atomic.Value
+
+	rec := &recordingServer{}
+	mux := http.NewServeMux()
+	mux.Handle("/sdk/v1/features/{feature_key}/", rec.handler(&calls, true, "on"))
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", capturingEvaluateHandler(&calls, &evaluated, true, "on"))
+
+	defaults := map[string]any{AttrService: "checkout", AttrEnvironment: "prod"}
+	client := newTestClient(t, mux, WithDefaultAttributes(defaults))
+
+	req := NewContext().WithUserID("u1").Set(AttrEnvironment, "canary")
+	res := client.Evaluate("new_ui", req)
+	require.NoError(t, res.Err())
+
+	assert.Equal(t, map[string]any{
+		AttrService:     "checkout",
+		AttrEnvironment: "canary",
+		AttrUserID:      "u1",
+	}, evaluated.Load())
+	assert.Len(t, req, 2)
+
+	ctx := context.Background()
+	require.NoError(t, client.TrackEvent(ctx, "new_ui", NewTrackEvent("A", EventTypeSuccess)))
+	require.NoError(t, client.ReportError(ctx, "new_ui", NewErrorReport("timeout", "slow").WithContext(AttrService, "api")))
+
+	assert.Equal(t, map[string]any{AttrService: "checkout", AttrEnvironment: "prod"}, rec.tracks[0]["context"])
+	assert.Equal(t, map[string]any{AttrService: "api", AttrEnvironment: "prod"}, rec.reports[0]["context"])
+}
+
+func TestClientDefaultAttributesFingerprint(t *testing.T) {
+	defaults := WithDefaultAttributes(map[string]any{AttrHostname: "host-1"})
+	req := NewContext().WithUserID("u1")
+
+	plain := newTestClient(t, http.NotFoundHandler())
+	without := newTestClient(t, http.NotFoundHandler(), defaults)
+	with := newTestClient(t, http.NotFoundHandler(), defaults, WithDefaultAttributesInCacheKey())
+
+	assert.Equal(t, plain.Fingerprint("f", req), without.Fingerprint("f", req))
+	assert.NotEqual(t, plain.Fingerprint("f", req), with.Fingerprint("f", req))
+	assert.Equal(t, plain.Fingerprint("f", NewContext().WithUserID("u1").Set(AttrHostname, "host-1")),
+		with.Fingerprint("f", req))
+}
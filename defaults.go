@@ -0,0 +1,42 @@
+package togglr
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// DefaultValue is the evaluation result used for a feature when the server
+// cannot be reached.
+type DefaultValue struct {
+	Enabled bool   `json:"enabled"`
+	Value   string `json:"value"`
+}
+
+func (d DefaultValue) result(featureKey string, err error) EvalResult {
+	return EvalResult{
+		featureKey: featureKey,
+		rawValue:   d.Value,
+		enabled:    d.Enabled,
+		found:      true,
+		err:        err,
+		source:     SourceDefault,
+	}
+}
+
+// LoadDefaults reads per-feature defaults from a JSON file of the form
+//
+//	{"new_ui": {"enabled": false}, "pool_size": {"enabled": true, "value": "8"}}
+func LoadDefaults(path string) (map[string]DefaultValue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read defaults: %w", err)
+	}
+
+	var defaults map[string]DefaultValue
+	if err := json.Unmarshal(data, &defaults); err != nil {
+		return nil, fmt.Errorf("parse defaults %s: %w", path, err)
+	}
+
+	return defaults, nil
+}
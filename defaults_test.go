@@ -0,0 +1,88 @@
+package togglr
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "defaults.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"new_ui": {"enabled": false},
+		"pool_size": {"enabled": true, "value": "8"}
+	}`), 0o600))
+
+	defaults, err := LoadDefaults(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]DefaultValue{
+		"new_ui":    {Enabled: false},
+		"pool_size": {Enabled: true, Value: "8"},
+	}, defaults)
+
+	_, err = LoadDefaults(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestClientEvaluateUsesDefaults(t *testing.T) {
+	down := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	client := newTestClient(t, down,
+		WithRetries(0),
+		WithDefaults(map[string]DefaultValue{
+			"pool_size": {Enabled: true, Value: "8"},
+		}),
+	)
+
+	res := client.Evaluate("pool_size", NewContext())
+	assert.Error(t, res.Err())
+	assert.Equal(t, SourceDefault, res.Source())
+	assert.True(t, res.Found())
+	assert.True(t, res.Enabled())
+	assert.Equal(t, "8", res.Value())
+
+	size, err := res.Int32()
+	require.NoError(t, err)
+	assert.Equal(t, int32(8), size)
+
+	value, err := res.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "8", value)
+
+	enabled, err := client.IsEnabled("pool_size", NewContext())
+	require.NoError(t, err)
+	assert.True(t, enabled)
+	assert.True(t, client.IsEnabledOrDefault("pool_size", NewContext(), false))
+
+	res = client.Evaluate("unknown", NewContext())
+	assert.Error(t, res.Err())
+	assert.Equal(t, SourceError, res.Source())
+	_, err = res.Result()
+	assert.Error(t, err)
+	_, err = client.IsEnabled("unknown", NewContext())
+	assert.Error(t, err)
+	assert.Equal(t, "", res.Value())
+	assert.False(t, client.IsEnabledOrDefault("unknown", NewContext(), false))
+}
+
+func TestClientEvaluateSource(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "on"))
+
+	client := newTestClient(t, mux, WithCache(10, time.Minute))
+
+	res := client.Evaluate("f", NewContext())
+	assert.Equal(t, SourceServer, res.Source())
+
+	res = client.Evaluate("f", NewContext())
+	assert.Equal(t, SourceCache, res.Source())
+}
@@ -0,0 +1,254 @@
+package togglr
+
+import (
+	"context"
+	"hash/fnv"
+	"maps"
+	mathrand "math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// ErrorReporting controls client-side aggregation of error reports.
+type ErrorReporting struct {
+	// Window collapses identical reports (same feature, error type and
+	// message fingerprint): the first one is sent right away, the rest are
+	// counted and sent as a single report with an "occurrences" context
+	// entry when the window ends. Zero disables aggregation.
+	Window time.Duration
+	// Rate and Burst configure a per-feature token bucket for reports that
+	// are actually sent. A zero Rate disables rate limiting.
+	Rate  float64
+	Burst int
+	// SampleRate is the probability of a report being considered at all.
+	// Zero means every report is considered.
+	SampleRate float64
+}
+
+const (
+	errorReportDroppedSampled     = "sampled"
+	errorReportDroppedRateLimited = "rate_limited"
+)
+
+type pendingReport struct {
+	featureKey string
+	report     *ErrorReport
+	count      int
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type errorAggregator struct {
+	cfg ErrorReporting
+
+	mu      sync.Mutex
+	pending map[string]*pendingReport
+	buckets map[string]*tokenBucket
+}
+
+func newErrorAggregator(cfg ErrorReporting) *errorAggregator {
+	return &errorAggregator{
+		cfg:     cfg,
+		pending: make(map[string]*pendingReport),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+func (a *errorAggregator) sampledOut() bool {
+	return a.cfg.SampleRate > 0 && a.cfg.SampleRate < 1 && mathrand.Float64() >= a.cfg.SampleRate
+}
+
+// admit decides the fate of report in one step: a duplicate of a report sent
+// in the current window is only counted, otherwise the rate limiter decides.
+// Reports that are let through start a window with a copy of report, so that
+// callers may reuse theirs.
+func (a *errorAggregator) admit(featureKey string, report *ErrorReport) (collapsed, allowed bool) {
+	key := featureKey + "\x00" + report.ErrorType + "\x00" + messageFingerprint(report.ErrorMessage)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cfg.Window > 0 {
+		if p, ok := a.pending[key]; ok {
+			p.count++
+
+			return true, false
+		}
+	}
+
+	if !a.allowLocked(featureKey) {
+		return false, false
+	}
+
+	if a.cfg.Window > 0 {
+		a.pending[key] = &pendingReport{
+			featureKey: featureKey,
+			report: &ErrorReport{
+				ErrorType:    report.ErrorType,
+				ErrorMessage: report.ErrorMessage,
+				Context:      maps.Clone(report.Context),
+			},
+		}
+	}
+
+	return false, true
+}
+
+func (a *errorAggregator) allow(featureKey string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.allowLocked(featureKey)
+}
+
+func (a *errorAggregator) allowLocked(featureKey string) bool {
+	if a.cfg.Rate <= 0 {
+		return true
+	}
+
+	burst := float64(a.cfg.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	now := time.Now()
+	b, ok := a.buckets[featureKey]
+	if !ok {
+		b = &tokenBucket{tokens: burst, last: now}
+		a.buckets[featureKey] = b
+	}
+
+	b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*a.cfg.Rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
+
+// drain returns the collapsed reports of the ending window, each carrying
+// the number of occurrences that were not sent yet.
+func (a *errorAggregator) drain() []pendingReport {
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = make(map[string]*pendingReport)
+	a.mu.Unlock()
+
+	var out []pendingReport
+	for _, p := range pending {
+		if p.count == 0 {
+			continue
+		}
+
+		report := &ErrorReport{
+			ErrorType:    p.report.ErrorType,
+			ErrorMessage: p.report.ErrorMessage,
+			Context:      maps.Clone(p.report.Context),
+		}
+		if report.Context == nil {
+			report.Context = make(map[string]any)
+		}
+		report.Context["occurrences"] = p.count
+		report.Context["window_seconds"] = a.cfg.Window.Seconds()
+
+		out = append(out, pendingReport{featureKey: p.featureKey, report: report, count: p.count})
+	}
+
+	return out
+}
+
+// messageFingerprint hashes msg with digit runs collapsed, so that messages
+// differing only in ids, durations or ports are considered identical.
+func messageFingerprint(msg string) string {
+	var b strings.Builder
+	b.Grow(len(msg))
+
+	inDigits := false
+	for _, r := range msg {
+		if unicode.IsDigit(r) {
+			if !inDigits {
+				b.WriteByte('#')
+			}
+			inDigits = true
+
+			continue
+		}
+		inDigits = false
+		b.WriteRune(r)
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(b.String()))
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// admitErrorReport applies sampling, aggregation and rate limiting. It
+// reports whether the report should be sent now.
+func (c *Client) admitErrorReport(featureKey string, report *ErrorReport) bool {
+	a := c.errAgg
+	if a == nil {
+		return true
+	}
+
+	if a.sampledOut() {
+		incErrorReportDropped(c.metrics, errorReportDroppedSampled)
+
+		return false
+	}
+
+	switch collapsed, allowed := a.admit(featureKey, report); {
+	case collapsed:
+		incErrorReportCollapsed(c.metrics)
+
+		return false
+	case !allowed:
+		incErrorReportDropped(c.metrics, errorReportDroppedRateLimited)
+
+		return false
+	}
+
+	return true
+}
+
+func (c *Client) runErrorAggregationLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.ErrorReporting.Window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			c.flushErrorReports()
+
+			return
+		case <-ticker.C:
+			c.flushErrorReports()
+		}
+	}
+}
+
+func (c *Client) flushErrorReports() {
+	for _, p := range c.errAgg.drain() {
+		if !c.errAgg.allow(p.featureKey) {
+			incErrorReportDropped(c.metrics, errorReportDroppedRateLimited)
+
+			continue
+		}
+
+		if err := c.sendErrorReport(context.Background(), p.featureKey, p.report); err != nil {
+			c.logger.Warn("failed to send aggregated error report",
+				"feature_key", p.featureKey, "occurrences", p.count, "error", err)
+		}
+	}
+}
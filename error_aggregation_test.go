@@ -0,0 +1,173 @@
+package togglr
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type errorReportMetrics struct {
+	NoOpMetrics
+	collapsed   atomic.Int32
+	sampled     atomic.Int32
+	rateLimited atomic.Int32
+}
+
+func (m *errorReportMetrics) IncErrorReportCollapsed() { m.collapsed.Add(1) }
+
+func (m *errorReportMetrics) IncErrorReportDropped(reason string) {
+	switch reason {
+	case errorReportDroppedSampled:
+		m.sampled.Add(1)
+	case errorReportDroppedRateLimited:
+		m.rateLimited.Add(1)
+	}
+}
+
+func TestMessageFingerprint(t *testing.T) {
+	assert.Equal(t,
+		messageFingerprint("dial tcp 10.0.0.1:5432: timeout after 5003ms"),
+		messageFingerprint("dial tcp 10.0.0.2:5433: timeout after 12ms"))
+	assert.NotEqual(t,
+		messageFingerprint("timeout"),
+		messageFingerprint("connection refused"))
+}
+
+func TestClientReportErrorAggregation(t *testing.T) {
+	var calls atomic.Int32
+	srv := &recordingServer{}
+	metrics := &errorReportMetrics{}
+
+	client := newTestClient(t, srv.handler(&calls, true, ""),
+		WithMetrics(metrics),
+		WithErrorReporting(ErrorReporting{Window: time.Hour}),
+	)
+
+	for i := 0; i < 10; i++ {
+		report := NewErrorReport("timeout", "request 42 timed out").WithContext("attempt", i)
+		require.NoError(t, client.ReportError(context.Background(), "f", report))
+	}
+	require.NoError(t, client.ReportError(context.Background(), "f", NewErrorReport("network", "refused")))
+
+	srv.mu.Lock()
+	assert.Len(t, srv.reports, 2)
+	srv.mu.Unlock()
+	assert.Equal(t, int32(9), metrics.collapsed.Load())
+
+	require.NoError(t, client.Close())
+
+	require.Len(t, srv.reports, 3)
+	flushed := srv.reports[2]
+	assert.Equal(t, "timeout", flushed["error_type"])
+	assert.Equal(t, map[string]any{
+		"attempt":        float64(0),
+		"occurrences":    float64(9),
+		"window_seconds": float64(3600),
+	}, flushed["context"])
+}
+
+func TestClientReportErrorRateLimit(t *testing.T) {
+	var calls atomic.Int32
+	srv := &recordingServer{}
+	metrics := &errorReportMetrics{}
+
+	client := newTestClient(t, srv.handler(&calls, true, ""),
+		WithMetrics(metrics),
+		WithErrorReporting(ErrorReporting{Rate: 0.001, Burst: 2}),
+	)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, client.ReportError(context.Background(), "f", NewErrorReport("e", "m")))
+	}
+	require.NoError(t, client.ReportError(context.Background(), "other", NewErrorReport("e", "m")))
+
+	assert.Len(t, srv.reports, 3)
+	assert.Equal(t, int32(3), metrics.rateLimited.Load())
+}
+
+// baseMetrics implements only the Metrics interface, like implementations
+// written before the optional interfaces were added.
+type baseMetrics struct{ Metrics }
+
+func TestClientReportErrorWithoutOptionalMetrics(t *testing.T) {
+	var calls atomic.Int32
+	srv := &recordingServer{}
+	metrics := &errorReportMetrics{}
+
+	client := newTestClient(t, srv.handler(&calls, true, ""),
+		WithMetrics(baseMetrics{metrics}),
+		WithErrorReporting(ErrorReporting{Window: time.Hour, Rate: 0.001, Burst: 1}),
+	)
+
+	for range 3 {
+		require.NoError(t, client.ReportError(context.Background(), "f", NewErrorReport("e", "m")))
+	}
+	require.NoError(t, client.ReportError(context.Background(), "f", NewErrorReport("other", "m")))
+
+	assert.Len(t, srv.reports, 1)
+	assert.Zero(t, metrics.collapsed.Load())
+	assert.Zero(t, metrics.rateLimited.Load())
+}
+
+func TestClientReportErrorSampling(t *testing.T) {
+	var calls atomic.Int32
+	srv := &recordingServer{}
+	metrics := &errorReportMetrics{}
+
+	client := newTestClient(t, srv.handler(&calls, true, ""),
+		WithMetrics(metrics),
+		WithErrorReporting(ErrorReporting{SampleRate: 0.5}),
+	)
+
+	const total = 200
+	for i := 0; i < total; i++ {
+		require.NoError(t, client.ReportError(context.Background(), "f", NewErrorReport("e", "m")))
+	}
+
+	sent := len(srv.reports)
+	assert.Equal(t, total, sent+int(metrics.sampled.Load()))
+	assert.InDelta(t, total/2, sent, total/4)
+}
+
+func TestClientReportErrorAggregationOwnsReports(t *testing.T) {
+	var calls atomic.Int32
+	srv := &recordingServer{}
+
+	client := newTestClient(t, srv.handler(&calls, true, ""),
+		WithErrorReporting(ErrorReporting{Window: time.Hour}),
+	)
+
+	report := NewErrorReport("timeout", "slow").WithContext("k", "v")
+	require.NoError(t, client.ReportError(context.Background(), "f", report))
+	report.Context["k"] = "changed"
+	require.NoError(t, client.ReportError(context.Background(), "f", report))
+
+	require.NoError(t, client.Close())
+
+	require.Len(t, srv.reports, 2)
+	assert.Equal(t, "v", srv.reports[1]["context"].(map[string]any)["k"])
+}
+
+func TestClientReportErrorRateLimitedNotFlushed(t *testing.T) {
+	var calls atomic.Int32
+	srv := &recordingServer{}
+
+	client := newTestClient(t, srv.handler(&calls, true, ""),
+		WithErrorReporting(ErrorReporting{Window: time.Hour, Rate: 20, Burst: 1}),
+	)
+
+	require.NoError(t, client.ReportError(context.Background(), "f", NewErrorReport("a", "first")))
+	require.NoError(t, client.ReportError(context.Background(), "f", NewErrorReport("b", "limited")))
+	require.NoError(t, client.ReportError(context.Background(), "f", NewErrorReport("b", "limited")))
+
+	// Let the bucket refill, so that the flush is not rate limited itself.
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, client.Close())
+
+	require.Len(t, srv.reports, 1)
+	assert.Equal(t, "a", srv.reports[0]["error_type"])
+}
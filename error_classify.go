@@ -0,0 +1,229 @@
+package togglr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	maxErrorCauses = 16
+	maxStackFrames = 32
+)
+
+// ErrorClassifier maps an error to a stable error type. It returns false if
+// it does not recognise the error.
+type ErrorClassifier func(err error) (errorType string, ok bool)
+
+type registeredClassifier struct {
+	id       uint64
+	classify ErrorClassifier
+}
+
+var errorClassifiers struct {
+	mu     sync.RWMutex
+	nextID uint64
+	fns    []registeredClassifier
+}
+
+// RegisterErrorClassifier adds a classifier consulted by
+// NewErrorReportFromError before the built-in rules. Classifiers are tried in
+// registration order and see every error in the chain. The returned function
+// removes the classifier; it is safe to call more than once.
+func RegisterErrorClassifier(fn ErrorClassifier) (unregister func()) {
+	errorClassifiers.mu.Lock()
+	defer errorClassifiers.mu.Unlock()
+
+	errorClassifiers.nextID++
+	id := errorClassifiers.nextID
+
+	// Readers keep using the slice they loaded, so it is never modified in place.
+	fns := make([]registeredClassifier, 0, len(errorClassifiers.fns)+1)
+	fns = append(fns, errorClassifiers.fns...)
+	errorClassifiers.fns = append(fns, registeredClassifier{id: id, classify: fn})
+
+	return func() {
+		errorClassifiers.mu.Lock()
+		defer errorClassifiers.mu.Unlock()
+
+		fns := make([]registeredClassifier, 0, len(errorClassifiers.fns))
+		for _, c := range errorClassifiers.fns {
+			if c.id != id {
+				fns = append(fns, c)
+			}
+		}
+		errorClassifiers.fns = fns
+	}
+}
+
+// NewErrorReportFromError builds an ErrorReport from err. The error type is
+// taken from registered classifiers, then from well-known sentinels
+// ("timeout", "canceled", "network"), then from the concrete Go type. The
+// unwrapped cause chain and the stack of the caller are added to the context.
+func NewErrorReportFromError(err error) *ErrorReport {
+	if err == nil {
+		err = errors.New("nil error")
+	}
+
+	report := NewErrorReport(errorTypeOf(err), err.Error())
+
+	if causes := errorCauses(err); len(causes) > 0 {
+		report.Context["causes"] = causes
+	}
+
+	if stack := callerStack(2); len(stack) > 0 {
+		report.Context["stack"] = stack
+	}
+
+	return report
+}
+
+func errorTypeOf(err error) string {
+	errorClassifiers.mu.RLock()
+	classifiers := errorClassifiers.fns
+	errorClassifiers.mu.RUnlock()
+
+	if len(classifiers) > 0 {
+		var found string
+		walkErrors(err, func(e error) bool {
+			for _, c := range classifiers {
+				if t, ok := c.classify(e); ok {
+					found = t
+
+					return false
+				}
+			}
+
+			return true
+		})
+
+		if found != "" {
+			return found
+		}
+	}
+
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return "timeout"
+		}
+
+		return "network"
+	}
+
+	name := "error"
+	walkErrors(err, func(e error) bool {
+		if t := fmt.Sprintf("%T", e); !genericErrorTypes[t] {
+			name = t
+
+			return false
+		}
+
+		return true
+	})
+
+	return name
+}
+
+var genericErrorTypes = map[string]bool{
+	"*errors.errorString": true,
+	"*fmt.wrapError":      true,
+	"*fmt.wrapErrors":     true,
+	"*errors.joinError":   true,
+}
+
+// walkErrors visits err and its causes depth first, following both
+// Unwrap() error and Unwrap() []error, until fn returns false.
+func walkErrors(err error, fn func(error) bool) {
+	stack := []error{err}
+
+	for len(stack) > 0 {
+		e := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if e == nil {
+			continue
+		}
+
+		if !fn(e) {
+			return
+		}
+
+		switch u := e.(type) {
+		case interface{ Unwrap() error }:
+			stack = append(stack, u.Unwrap())
+		case interface{ Unwrap() []error }:
+			wrapped := u.Unwrap()
+			for i := len(wrapped) - 1; i >= 0; i-- {
+				stack = append(stack, wrapped[i])
+			}
+		}
+	}
+}
+
+func errorCauses(err error) []map[string]any {
+	var causes []map[string]any
+
+	first := true
+	walkErrors(err, func(e error) bool {
+		if first {
+			first = false
+
+			return true
+		}
+
+		causes = append(causes, map[string]any{
+			"type":    fmt.Sprintf("%T", e),
+			"message": e.Error(),
+		})
+
+		return len(causes) < maxErrorCauses
+	})
+
+	return causes
+}
+
+var sdkDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+
+	return filepath.Dir(file)
+}()
+
+// callerStack returns up to maxStackFrames frames of the calling goroutine as
+// "function file:line", without leading SDK frames and runtime frames.
+func callerStack(skip int) []string {
+	pcs := make([]uintptr, maxStackFrames*2)
+	n := runtime.Callers(skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []string
+	leading := true
+
+	for {
+		frame, more := frames.Next()
+
+		inSDK := filepath.Dir(frame.File) == sdkDir && !strings.HasSuffix(frame.File, "_test.go")
+		if !(leading && inSDK) && !strings.HasPrefix(frame.Function, "runtime.") {
+			leading = false
+			stack = append(stack, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+
+		if !more || len(stack) == maxStackFrames {
+			break
+		}
+	}
+
+	return stack
+}
@@ -0,0 +1,96 @@
+package togglr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type quotaError struct{ limit int }
+
+func (e *quotaError) Error() string { return fmt.Sprintf("quota of %d exceeded", e.limit) }
+
+type classifiedError struct{}
+
+func (classifiedError) Error() string { return "classified" }
+
+func TestErrorTypeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), "timeout"},
+		{"canceled", context.Canceled, "canceled"},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, "network"},
+		{"concrete type", fmt.Errorf("wrapped: %w", &quotaError{limit: 10}), "*togglr.quotaError"},
+		{"joined", errors.Join(errors.New("first"), &quotaError{limit: 1}), "*togglr.quotaError"},
+		{"plain", errors.New("boom"), "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errorTypeOf(tt.err))
+		})
+	}
+}
+
+func TestRegisterErrorClassifier(t *testing.T) {
+	unregister := RegisterErrorClassifier(func(err error) (string, bool) {
+		if _, ok := err.(classifiedError); ok {
+			return "custom", true
+		}
+
+		return "", false
+	})
+
+	t.Cleanup(unregister)
+
+	assert.Equal(t, "custom", errorTypeOf(fmt.Errorf("outer: %w", classifiedError{})))
+	assert.Equal(t, "error", errorTypeOf(errors.New("other")))
+
+	unregister()
+	unregister()
+	assert.Equal(t, "togglr.classifiedError", errorTypeOf(classifiedError{}))
+}
+
+func TestNewErrorReportFromError(t *testing.T) {
+	err := fmt.Errorf("charge failed: %w", errors.Join(
+		&quotaError{limit: 5},
+		fmt.Errorf("upstream: %w", context.DeadlineExceeded),
+	))
+
+	report := NewErrorReportFromError(err)
+
+	assert.Equal(t, "timeout", report.ErrorType)
+	assert.Equal(t, err.Error(), report.ErrorMessage)
+
+	causes, ok := report.Context["causes"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, causes, 4)
+	assert.Equal(t, "*errors.joinError", causes[0]["type"])
+	assert.Equal(t, "*togglr.quotaError", causes[1]["type"])
+	assert.Equal(t, "quota of 5 exceeded", causes[1]["message"])
+	assert.Equal(t, "upstream: context deadline exceeded", causes[2]["message"])
+
+	stack, ok := report.Context["stack"].([]string)
+	require.True(t, ok)
+	require.NotEmpty(t, stack)
+	assert.True(t, strings.HasPrefix(stack[0], "github.com/togglr-project/togglr-sdk-go.TestNewErrorReportFromError "), stack[0])
+	for _, frame := range stack {
+		assert.False(t, strings.HasPrefix(frame, "runtime."), frame)
+	}
+}
+
+func TestNewErrorReportFromErrorWithoutCauses(t *testing.T) {
+	report := NewErrorReportFromError(errors.New("boom"))
+
+	assert.Equal(t, "error", report.ErrorType)
+	assert.NotContains(t, report.Context, "causes")
+}
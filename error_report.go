@@ -2,12 +2,9 @@ package togglr
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
-	"github.com/go-faster/jx"
-
 	api "github.com/togglr-project/togglr-sdk-go/internal/generated/client"
 )
 
@@ -37,10 +34,36 @@ func (er *ErrorReport) WithContexts(contexts map[string]any) *ErrorReport {
 	return er
 }
 
+// ReportError sends report for featureKey. With Config.ErrorReporting set,
+// reports may be sampled out, collapsed or rate limited on the client, in
+// which case nil is returned without a request being made.
 func (c *Client) ReportError(
 	ctx context.Context,
 	featureKey string,
 	report *ErrorReport,
+) error {
+	_, err := c.withHooks(ctx, OperationReportError, featureKey, report.Context,
+		func(req RequestContext) (*EvalResult, error) {
+			attrs, err := c.prepareAttributes(OperationReportError, featureKey, req)
+			if err != nil {
+				return nil, err
+			}
+
+			report := &ErrorReport{ErrorType: report.ErrorType, ErrorMessage: report.ErrorMessage, Context: attrs}
+			if !c.admitErrorReport(featureKey, report) {
+				return nil, nil
+			}
+
+			return nil, c.sendErrorReport(ctx, featureKey, report)
+		})
+
+	return err
+}
+
+func (c *Client) sendErrorReport(
+	ctx context.Context,
+	featureKey string,
+	report *ErrorReport,
 ) error {
 	start := time.Now()
 	c.metrics.IncErrorReportRequest()
@@ -53,6 +76,10 @@ func (c *Client) ReportError(
 	c.metrics.ObserveErrorReportLatency(time.Since(start))
 	if err != nil {
 		c.metrics.IncErrorReportError("report_error_failed")
+		c.debug.recordError("report_error", featureKey, err)
+		c.stats.markFailure(err)
+	} else {
+		c.stats.markSuccess()
 	}
 
 	return err
@@ -82,13 +109,8 @@ func (c *Client) reportErrorWithRetries(
 			ErrorMessage: report.ErrorMessage,
 		}
 
-		if len(report.Context) > 0 {
-			contextData := make(api.FeatureErrorReportContext)
-			for k, v := range report.Context {
-				if raw, err := json.Marshal(v); err == nil {
-					contextData[k] = jx.Raw(raw)
-				}
-			}
+		if attrs := mergeAttributes(c.defaultAttrs, report.Context); len(attrs) > 0 {
+			contextData := api.FeatureErrorReportContext(encodeAttributes(attrs, c.logger))
 			apiReq.Context = api.NewOptFeatureErrorReportContext(contextData)
 		}
 
@@ -12,6 +12,9 @@ var (
 	ErrFeatureNotFound     = errors.New("feature not found")
 	ErrBadRequest          = errors.New("bad request")
 	ErrInternalServerError = errors.New("internal server error")
+	ErrCacheDisabled       = errors.New("cache is disabled")
+	ErrInvalidAttribute    = errors.New("invalid attribute")
+	ErrInvalidStruct       = errors.New("invalid context struct")
 )
 
 type APIError struct {
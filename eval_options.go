@@ -0,0 +1,86 @@
+package togglr
+
+import (
+	"time"
+)
+
+// EvalOption overrides the client configuration for a single evaluation.
+type EvalOption func(*evalOptions)
+
+type evalOptions struct {
+	noCache bool
+	timeout time.Duration
+	retries int
+	def     *DefaultValue
+	extra   map[string]any
+}
+
+func (c *Client) newEvalOptions(opts []EvalOption) evalOptions {
+	o := evalOptions{
+		timeout: c.cfg.Timeout,
+		retries: c.cfg.Retries,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// defaultFor returns the per-call default if set, otherwise the configured
+// one for featureKey.
+func (o *evalOptions) defaultFor(featureKey string, defaults map[string]DefaultValue) (DefaultValue, bool) {
+	if o.def != nil {
+		return *o.def, true
+	}
+
+	def, ok := defaults[featureKey]
+
+	return def, ok
+}
+
+// WithNoCache neither reads nor stores the evaluation in the cache.
+func WithNoCache() EvalOption {
+	return func(o *evalOptions) {
+		o.noCache = true
+	}
+}
+
+// WithCallTimeout overrides Config.Timeout for the call. A non-positive d
+// keeps the configured timeout.
+func WithCallTimeout(d time.Duration) EvalOption {
+	return func(o *evalOptions) {
+		if d > 0 {
+			o.timeout = d
+		}
+	}
+}
+
+// WithCallRetries overrides Config.Retries for the call. A negative n means
+// no retries.
+func WithCallRetries(n int) EvalOption {
+	return func(o *evalOptions) {
+		o.retries = max(n, 0)
+	}
+}
+
+// WithDefaultValue sets the result returned when the evaluation fails,
+// taking precedence over Config.Defaults.
+func WithDefaultValue(enabled bool, value string) EvalOption {
+	return func(o *evalOptions) {
+		o.def = &DefaultValue{Enabled: enabled, Value: value}
+	}
+}
+
+// WithExtraAttributes adds attributes to the request context of the call.
+// They override attributes of the same name.
+func WithExtraAttributes(attrs map[string]any) EvalOption {
+	return func(o *evalOptions) {
+		if o.extra == nil {
+			o.extra = make(map[string]any, len(attrs))
+		}
+		for k, v := range attrs {
+			o.extra[k] = v
+		}
+	}
+}
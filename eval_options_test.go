@@ -0,0 +1,141 @@
+package togglr
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalOptionNoCache(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "on"))
+
+	client := newTestClient(t, mux, WithCache(10, time.Minute))
+	req := NewContext().WithUserID("u1")
+
+	client.Evaluate("checkout", req, WithNoCache())
+	client.Evaluate("checkout", req, WithNoCache())
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Zero(t, client.cache.Size())
+
+	client.Evaluate("checkout", req)
+	res := client.Evaluate("checkout", req)
+	assert.Equal(t, SourceCache, res.Source())
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestEvalOptionRetriesAndTimeout(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.PathValue("feature_key") == "slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	client := newTestClient(t, mux, WithRetries(3))
+
+	client.Evaluate("flaky", NewContext(), WithCallRetries(0))
+	assert.Equal(t, int32(1), calls.Load())
+
+	res := client.Evaluate("slow", NewContext(), WithCallTimeout(10*time.Millisecond))
+	assert.ErrorIs(t, res.Err(), context.DeadlineExceeded)
+}
+
+func TestEvalOptionCallTimeoutLongerThanConfig(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(150 * time.Millisecond)
+		evaluateHandler(&calls, true, "on")(w, r)
+	})
+
+	client := newTestClient(t, mux, WithTimeout(50*time.Millisecond), WithRetries(0))
+
+	res := client.Evaluate("job", NewContext())
+	assert.ErrorIs(t, res.Err(), context.DeadlineExceeded)
+
+	res = client.Evaluate("job", NewContext(), WithCallTimeout(2*time.Second))
+	require.NoError(t, res.Err())
+	assert.True(t, res.Enabled())
+}
+
+func TestEvalOptionRetriesAndTimeoutOutOfRange(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"feature_key": r.PathValue("feature_key"), "enabled": true, "value": "on"})
+	})
+
+	client := newTestClient(t, mux, WithCache(10, time.Minute))
+
+	res := client.Evaluate("flaky", NewContext(), WithCallRetries(-1))
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Error(t, res.Err(), "a failed call must not read as feature not found")
+	assert.Zero(t, client.cache.Size())
+
+	res = client.Evaluate("flaky", NewContext(), WithCallTimeout(0))
+	require.NoError(t, res.Err())
+	assert.True(t, res.Enabled())
+
+	res = client.Evaluate("other", NewContext(), WithNoCache(), WithCallTimeout(-time.Second))
+	require.NoError(t, res.Err())
+}
+
+func TestEvalOptionDefaultValue(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	client := newTestClient(t, mux,
+		WithRetries(0),
+		WithDefaults(map[string]DefaultValue{"new_ui": {Enabled: false}}),
+	)
+
+	res := client.Evaluate("new_ui", NewContext(), WithDefaultValue(true, "blue"))
+	assert.Equal(t, SourceDefault, res.Source())
+	assert.True(t, res.Enabled())
+	assert.Equal(t, "blue", res.Value())
+
+	assert.False(t, client.IsEnabledOrDefault("new_ui", NewContext(), true))
+	assert.True(t, client.IsEnabledOrDefault("new_ui", NewContext(), false, WithDefaultValue(true, "")))
+	assert.True(t, client.IsEnabledOrDefault("other", NewContext(), true))
+}
+
+func TestEvalOptionExtraAttributes(t *testing.T) {
+	var calls atomic.Int32
+	var seen atomic.Value
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", capturingEvaluateHandler(&calls, &seen, true, "on"))
+
+	client := newTestClient(t, mux)
+
+	req := NewContext().WithUserID("u1").WithCountry("DE")
+	res := client.Evaluate("new_ui", req, WithExtraAttributes(map[string]any{
+		AttrCountryCode: "FR",
+		"job":           "reindex",
+	}))
+	require.NoError(t, res.Err())
+
+	body := seen.Load().(map[string]any)
+	assert.Equal(t, "u1", body[AttrUserID])
+	assert.Equal(t, "FR", body[AttrCountryCode])
+	assert.Equal(t, "reindex", body["job"])
+	assert.Equal(t, "DE", req[AttrCountryCode])
+	assert.NotContains(t, req, "job")
+}
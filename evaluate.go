@@ -2,15 +2,12 @@ package togglr
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"time"
 
-	"github.com/go-faster/jx"
-
-	"github.com/togglr-project/togglr-sdk-go/internal/fingerprint"
 	api "github.com/togglr-project/togglr-sdk-go/internal/generated/client"
 )
 
@@ -18,17 +15,96 @@ func (c *Client) EvaluateWithContext(
 	ctx context.Context,
 	featureKey string,
 	req RequestContext,
+	opts ...EvalOption,
+) EvalResult {
+	o := c.newEvalOptions(opts)
+
+	if len(o.extra) > 0 {
+		merged := make(RequestContext, len(req)+len(o.extra))
+		maps.Copy(merged, req)
+		maps.Copy(merged, o.extra)
+		req = merged
+	}
+
+	var res EvalResult
+
+	ran, err := c.withHooks(ctx, OperationEvaluate, featureKey, req, func(req RequestContext) (*EvalResult, error) {
+		attrs, err := c.prepareAttributes(OperationEvaluate, featureKey, req)
+		if err != nil {
+			res = c.failedResult(featureKey, err, &o)
+
+			return &res, err
+		}
+
+		start := time.Now()
+		res = c.evaluate(ctx, featureKey, attrs, &o)
+		c.debug.recordEvaluation(featureKey, attrs, res, time.Since(start))
+
+		return &res, res.err
+	})
+	if !ran {
+		c.logger.Debug("evaluation aborted by hook", "feature_key", featureKey, "error", err)
+
+		return c.failedResult(featureKey, err, &o)
+	}
+
+	return res
+}
+
+// failedResult is the result of an evaluation that failed before reaching
+// the server: the default for featureKey if there is one, err otherwise.
+func (c *Client) failedResult(featureKey string, err error, o *evalOptions) EvalResult {
+	if def, ok := o.defaultFor(featureKey, c.cfg.Defaults); ok {
+		return def.result(featureKey, err)
+	}
+
+	return EvalResult{featureKey: featureKey, err: err, source: SourceError}
+}
+
+func (c *Client) evaluate(
+	ctx context.Context,
+	featureKey string,
+	req RequestContext,
+	o *evalOptions,
 ) EvalResult {
 	start := time.Now()
 	c.metrics.IncEvaluateRequest()
 
-	var cacheKey string
-	if c.cache != nil {
-		cacheKey = fmt.Sprintf("%s:%s", featureKey, fingerprint.Fingerprint(req))
+	if c.guard != nil && c.guard.autoDisabled(featureKey) {
+		c.logger.Debug("feature auto-disabled, skipping evaluation", "feature_key", featureKey)
+
+		return EvalResult{
+			featureKey: featureKey,
+			enabled:    false,
+			found:      true,
+			source:     SourceHealth,
+		}
+	}
+
+	cache := c.cache
+	if o.noCache {
+		cache = nil
+	}
+
+	var key, fp string
+	if cache != nil {
+		fp = c.fingerprint(featureKey, req)
+		key = cacheKey(featureKey, fp)
+
+		entry, hit := cache.Get(key)
+		if c.hot != nil {
+			c.hot.record(key, featureKey, req, hit)
+		}
 
-		if entry, hit := c.cache.Get(cacheKey); hit {
+		if hit {
 			c.metrics.IncCacheHit()
-			c.logger.Debug("cache hit", "feature_key", featureKey, "cache_key", cacheKey)
+			c.stats.cacheHits.Add(1)
+			c.logger.Debug("cache hit", "feature_key", featureKey, "cache_key", key)
+
+			source := SourceCache
+			if entry.Stale {
+				source = SourceStale
+			}
 
 			return EvalResult{
 				featureKey: featureKey,
@@ -36,23 +112,78 @@ func (c *Client) EvaluateWithContext(
 				enabled:    entry.Enabled,
 				found:      entry.Found,
 				err:        nil,
+				source:     source,
 			}
 		}
 		c.metrics.IncCacheMiss()
+		c.stats.cacheMisses.Add(1)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	gen := c.gens.current(featureKey)
+
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
 	defer cancel()
 
-	value, enabled, found, err := c.evaluateWithRetries(ctx, featureKey, req)
+	value, enabled, found, err := c.evaluateWithRetries(ctx, featureKey, req, o.retries)
 
 	c.metrics.ObserveEvaluateLatency(time.Since(start))
 	if err != nil {
 		c.metrics.IncEvaluateError(getErrorCode(err))
+		c.debug.recordError("evaluate", featureKey, err)
+		c.stats.markFailure(err)
+	} else {
+		c.stats.markSuccess()
+		c.stats.warm.Store(true)
 	}
 
-	if err == nil && c.cache != nil {
-		c.cache.Set(cacheKey, value, enabled, found)
+	if cache != nil {
+		if err == nil {
+			if c.keyer != nil && c.keyer.policy.ServerHints {
+				// The response may have updated the feature's attribute set.
+				fp = c.fingerprint(featureKey, req)
+				key = cacheKey(featureKey, fp)
+			}
+
+			// A flag change received during the request may have invalidated
+			// the response already.
+			stored := c.gens.ifCurrent(featureKey, gen, func() {
+				cache.SetEntry(key, &CacheEntry{
+					FeatureKey:  featureKey,
+					Fingerprint: fp,
+					Value:       value,
+					Enabled:     enabled,
+					Found:       found,
+				})
+			})
+			if !stored {
+				c.logger.Debug("flag changed during evaluation, not caching", "feature_key", featureKey)
+			}
+		} else if entry, ok := cache.GetStale(key); ok && entry.Stale {
+			c.logger.Warn("evaluation failed, using stale snapshot entry",
+				"feature_key", featureKey, "error", err, "stored_at", entry.StoredAt)
+
+			return EvalResult{
+				featureKey: featureKey,
+				rawValue:   entry.Value,
+				enabled:    entry.Enabled,
+				found:      entry.Found,
+				err:        err,
+				source:     SourceStale,
+			}
+		}
+	}
+
+	if err != nil {
+		if def, ok := o.defaultFor(featureKey, c.cfg.Defaults); ok {
+			c.logger.Warn("evaluation failed, using configured default",
+				"feature_key", featureKey, "error", err, "enabled", def.Enabled, "value", def.Value)
+
+			return def.result(featureKey, err)
+		}
+	}
+
+	if err != nil {
+		return EvalResult{featureKey: featureKey, err: err, source: SourceError}
 	}
 
 	return EvalResult{
@@ -60,18 +191,31 @@ func (c *Client) EvaluateWithContext(
 		rawValue:   value,
 		enabled:    enabled,
 		found:      found,
-		err:        err,
+		source:     SourceServer,
 	}
 }
 
-func (c *Client) Evaluate(featureKey string, req RequestContext) EvalResult {
-	return c.EvaluateWithContext(context.Background(), featureKey, req)
+func cacheKey(featureKey, fp string) string {
+	return featureKey + ":" + fp
+}
+
+// EvaluateCtx evaluates featureKey for the attributes added to ctx with
+// WithRequestContext.
+func (c *Client) EvaluateCtx(ctx context.Context, featureKey string, opts ...EvalOption) EvalResult {
+	return c.EvaluateWithContext(ctx, featureKey, RequestContextFrom(ctx), opts...)
+}
+
+func (c *Client) Evaluate(featureKey string, req RequestContext, opts ...EvalOption) EvalResult {
+	return c.EvaluateWithContext(context.Background(), featureKey, req, opts...)
 }
 
-func (c *Client) IsEnabled(featureKey string, req RequestContext) (bool, error) {
-	res := c.Evaluate(featureKey, req)
-	if err := res.Err(); err != nil {
-		return false, err
+// IsEnabled reports whether featureKey is enabled for req. A default or
+// stale value used because the evaluation failed is returned without error;
+// use Evaluate to tell them apart.
+func (c *Client) IsEnabled(featureKey string, req RequestContext, opts ...EvalOption) (bool, error) {
+	res := c.Evaluate(featureKey, req, opts...)
+	if res.failed() {
+		return false, res.Err()
 	}
 
 	if !res.Found() {
@@ -81,26 +225,40 @@ func (c *Client) IsEnabled(featureKey string, req RequestContext) (bool, error)
 	return res.Enabled(), nil
 }
 
-func (c *Client) IsEnabledOrDefault(featureKey string, req RequestContext, def bool) bool {
-	enabled, err := c.IsEnabled(featureKey, req)
-	if err != nil {
-		c.logger.Warn("evaluation failed, using default",
-			"feature_key", featureKey, "error", err, "default", def)
-
-		return def
+// IsEnabledOrDefault returns def when the evaluation fails, unless a default
+// for featureKey is configured in Config.Defaults or given by WithDefaultValue.
+func (c *Client) IsEnabledOrDefault(featureKey string, req RequestContext, def bool, opts ...EvalOption) bool {
+	res := c.Evaluate(featureKey, req, opts...)
+
+	var err error
+	switch {
+	case res.failed():
+		err = res.Err()
+	case !res.Found():
+		err = ErrFeatureNotFound
+	default:
+		return res.Enabled()
 	}
 
-	return enabled
+	c.logger.Warn("evaluation failed, using default",
+		"feature_key", featureKey, "error", err, "default", def)
+
+	return def
 }
 
 func (c *Client) evaluateWithRetries(
 	ctx context.Context,
 	featureKey string,
 	req RequestContext,
+	retries int,
 ) (string, bool, bool, error) {
 	var lastErr error
 
-	for attempt := 0; attempt <= c.cfg.Retries; attempt++ {
+	attrs := mergeAttributes(c.defaultAttrs, req)
+
+	// At least one attempt is made, otherwise the zero result would read as
+	// "feature not found".
+	for attempt := 0; attempt <= max(retries, 0); attempt++ {
 		if attempt > 0 {
 			delay := c.calculateBackoffDelay(attempt)
 			c.logger.Debug("retrying after delay", "attempt", attempt, "delay", delay)
@@ -112,12 +270,7 @@ func (c *Client) evaluateWithRetries(
 			}
 		}
 
-		evalReq := make(api.EvaluateRequest, len(req))
-		for k, v := range req {
-			if raw, err := json.Marshal(v); err == nil {
-				evalReq[k] = jx.Raw(raw)
-			}
-		}
+		evalReq := api.EvaluateRequest(encodeAttributes(attrs, c.logger))
 
 		params := api.SdkV1FeaturesFeatureKeyEvaluatePostParams{
 			FeatureKey: featureKey,
@@ -127,6 +280,8 @@ func (c *Client) evaluateWithRetries(
 		if err == nil {
 			switch r := resp.(type) {
 			case *api.EvaluateResponse:
+				c.keyer.rememberHint(featureKey, r.Attributes)
+
 				return r.Value, r.Enabled, true, nil
 			case *api.ErrorNotFound:
 				return "", false, false, nil
@@ -170,11 +325,15 @@ func (c *Client) evaluateWithRetries(
 }
 
 func (c *Client) calculateBackoffDelay(attempt int) time.Duration {
-	delay := c.cfg.Backoff.BaseDelay
+	return backoffDelay(c.cfg.Backoff, attempt)
+}
+
+func backoffDelay(b Backoff, attempt int) time.Duration {
+	delay := b.BaseDelay
 	for i := 1; i < attempt; i++ {
-		delay = time.Duration(float64(delay) * c.cfg.Backoff.Factor)
-		if delay > c.cfg.Backoff.MaxDelay {
-			delay = c.cfg.Backoff.MaxDelay
+		delay = time.Duration(float64(delay) * b.Factor)
+		if delay > b.MaxDelay {
+			delay = b.MaxDelay
 
 			break
 		}
@@ -8,14 +8,44 @@ import (
 	"time"
 )
 
+type EvalSource string
+
+const (
+	SourceServer  EvalSource = "server"
+	SourceCache   EvalSource = "cache"
+	SourceStale   EvalSource = "stale"
+	SourceDefault EvalSource = "default"
+	SourceHealth  EvalSource = "health"
+	// SourceError marks a failed evaluation without a fallback value: the
+	// server could not be reached or answered with an error, a hook aborted
+	// the call or its attributes were rejected.
+	SourceError EvalSource = "error"
+)
+
 type EvalResult struct {
 	featureKey string
 	rawValue   string
 	enabled    bool
 	found      bool
 	err        error
+	source     EvalSource
+}
+
+// failed reports whether the result carries no usable value. Defaults and
+// stale snapshot entries are usable even though the evaluation error is kept.
+func (r *EvalResult) failed() bool {
+	return r.err != nil && r.source != SourceDefault && r.source != SourceStale
 }
 
+// fromServer reports whether the value was assigned by the server, directly
+// or through the cache, rather than taken from a default or a snapshot.
+func (r *EvalResult) fromServer() bool {
+	return r.source == SourceServer || r.source == SourceCache
+}
+
+// Err returns the error of the evaluation, also when a default or stale
+// value was used instead. The value getters (Result, Bool, Int32, ...) only
+// return it when there is no such value, that is when Source is SourceError.
 func (r *EvalResult) Err() error {
 	return r.err
 }
@@ -33,19 +63,26 @@ func (r *EvalResult) Value() string {
 		return ""
 	}
 
-	if r.err != nil {
+	if r.failed() {
 		return ""
 	}
 
 	return r.rawValue
 }
 
+// Source tells where the result came from: the server, the cache, a stale
+// snapshot entry, a configured default, the auto-disable state or, for failed
+// evaluations without a fallback, SourceError.
+func (r *EvalResult) Source() EvalSource {
+	return r.source
+}
+
 func (r *EvalResult) FeatureKey() string {
 	return r.featureKey
 }
 
 func (r *EvalResult) Result() (string, error) {
-	if r.err != nil {
+	if r.failed() {
 		return "", r.err
 	}
 
@@ -53,7 +90,7 @@ func (r *EvalResult) Result() (string, error) {
 }
 
 func (r *EvalResult) Bool() (bool, error) {
-	if r.err != nil {
+	if r.failed() {
 		return false, r.err
 	}
 
@@ -72,7 +109,7 @@ func (r *EvalResult) Bool() (bool, error) {
 }
 
 func (r *EvalResult) Int32() (int32, error) {
-	if r.err != nil {
+	if r.failed() {
 		return 0, r.err
 	}
 
@@ -93,7 +130,7 @@ func (r *EvalResult) Int32() (int32, error) {
 }
 
 func (r *EvalResult) UInt32() (uint32, error) {
-	if r.err != nil {
+	if r.failed() {
 		return 0, r.err
 	}
 
@@ -114,7 +151,7 @@ func (r *EvalResult) UInt32() (uint32, error) {
 }
 
 func (r *EvalResult) Float32() (float32, error) {
-	if r.err != nil {
+	if r.failed() {
 		return 0, r.err
 	}
 
@@ -135,7 +172,7 @@ func (r *EvalResult) Float32() (float32, error) {
 }
 
 func (r *EvalResult) Int64() (int64, error) {
-	if r.err != nil {
+	if r.failed() {
 		return 0, r.err
 	}
 
@@ -151,7 +188,7 @@ func (r *EvalResult) Int64() (int64, error) {
 }
 
 func (r *EvalResult) UInt64() (uint64, error) {
-	if r.err != nil {
+	if r.failed() {
 		return 0, r.err
 	}
 
@@ -167,7 +204,7 @@ func (r *EvalResult) UInt64() (uint64, error) {
 }
 
 func (r *EvalResult) Float64() (float64, error) {
-	if r.err != nil {
+	if r.failed() {
 		return 0, r.err
 	}
 
@@ -183,7 +220,7 @@ func (r *EvalResult) Float64() (float64, error) {
 }
 
 func (r *EvalResult) JSON(v interface{}) error {
-	if r.err != nil {
+	if r.failed() {
 		return r.err
 	}
 
@@ -199,7 +236,7 @@ func (r *EvalResult) JSON(v interface{}) error {
 }
 
 func (r *EvalResult) Duration() (time.Duration, error) {
-	if r.err != nil {
+	if r.failed() {
 		return 0, r.err
 	}
 
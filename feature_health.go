@@ -18,7 +18,30 @@ type FeatureHealth struct {
 	LastErrorAt    *time.Time
 }
 
+// ThresholdRatio returns ErrorRate as a fraction of Threshold. It returns
+// false if either is unknown or Threshold is not positive.
+func (h *FeatureHealth) ThresholdRatio() (float64, bool) {
+	if h.ErrorRate == nil || h.Threshold == nil || *h.Threshold <= 0 {
+		return 0, false
+	}
+
+	return float64(*h.ErrorRate) / float64(*h.Threshold), true
+}
+
 func (c *Client) GetFeatureHealth(ctx context.Context, featureKey string) (*FeatureHealth, error) {
+	var health *FeatureHealth
+
+	_, err := c.withHooks(ctx, OperationHealth, featureKey, nil, func(RequestContext) (*EvalResult, error) {
+		var err error
+		health, err = c.getFeatureHealth(ctx, featureKey)
+
+		return nil, err
+	})
+
+	return health, err
+}
+
+func (c *Client) getFeatureHealth(ctx context.Context, featureKey string) (*FeatureHealth, error) {
 	start := time.Now()
 	c.metrics.IncFeatureHealthRequest()
 
@@ -30,6 +53,10 @@ func (c *Client) GetFeatureHealth(ctx context.Context, featureKey string) (*Feat
 	c.metrics.ObserveFeatureHealthLatency(time.Since(start))
 	if err != nil {
 		c.metrics.IncFeatureHealthError("get_health_failed")
+		c.debug.recordError("health", featureKey, err)
+		c.stats.markFailure(err)
+	} else {
+		c.stats.markSuccess()
 	}
 
 	return health, err
@@ -0,0 +1,286 @@
+package togglr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/togglr-project/togglr-sdk-go/internal/generated/client"
+)
+
+const (
+	featuresHealthBatchSize   = 100
+	featuresHealthConcurrency = 8
+)
+
+var errBulkHealthUnsupported = errors.New("bulk health is not supported by the server")
+
+// FeaturesHealthError is returned by GetFeaturesHealth when the health of some
+// features could not be retrieved. Features not found fail with
+// ErrFeatureNotFound.
+type FeaturesHealthError struct {
+	Errors map[string]error
+}
+
+func (e *FeaturesHealthError) Error() string {
+	if len(e.Errors) == 0 {
+		return "failed to get health of features"
+	}
+
+	keys := make([]string, 0, len(e.Errors))
+	for k := range e.Errors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return fmt.Sprintf("failed to get health of %d feature(s), %s: %v", len(keys), keys[0], e.Errors[keys[0]])
+}
+
+func (e *FeaturesHealthError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// GetFeaturesHealth returns the health of every key that could be retrieved.
+// It uses the bulk health endpoint and falls back to per-key requests if the
+// server does not support it, or for a batch the server failed with a 5xx.
+// If any key fails, the result is still returned together with a
+// *FeaturesHealthError.
+func (c *Client) GetFeaturesHealth(ctx context.Context, keys []string) (map[string]*FeatureHealth, error) {
+	var result map[string]*FeatureHealth
+
+	req := RequestContext{"feature_keys": slices.Clone(keys)}
+
+	_, err := c.withHooks(ctx, OperationHealth, "", req, func(req RequestContext) (*EvalResult, error) {
+		if k, ok := req["feature_keys"].([]string); ok {
+			keys = k
+		}
+
+		var err error
+		result, err = c.getFeaturesHealth(ctx, keys)
+
+		return nil, err
+	})
+
+	return result, err
+}
+
+func (c *Client) getFeaturesHealth(ctx context.Context, keys []string) (map[string]*FeatureHealth, error) {
+	keys = slices.Compact(slices.Sorted(slices.Values(keys)))
+
+	var mu sync.Mutex
+	result := make(map[string]*FeatureHealth, len(keys))
+	failed := make(map[string]error)
+	fallback := keys
+
+	if !c.bulkHealthUnsupported.Load() {
+		fallback = nil
+		batches := slices.Collect(slices.Chunk(keys, featuresHealthBatchSize))
+
+		forEachLimited(len(batches), featuresHealthConcurrency, func(i int) {
+			health, notFound, err := c.getFeaturesHealthBatch(ctx, batches[i])
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, key := range batches[i] {
+				switch h, ok := health[key]; {
+				case errors.Is(err, errBulkHealthUnsupported), isServerError(err):
+					fallback = append(fallback, key)
+				case err != nil:
+					failed[key] = err
+				case ok:
+					result[key] = h
+				case slices.Contains(notFound, key):
+					failed[key] = ErrFeatureNotFound
+				default:
+					fallback = append(fallback, key)
+				}
+			}
+		})
+	}
+
+	forEachLimited(len(fallback), featuresHealthConcurrency, func(i int) {
+		health, err := c.getFeatureHealth(ctx, fallback[i])
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			failed[fallback[i]] = err
+		} else {
+			result[fallback[i]] = health
+		}
+	})
+
+	if len(failed) > 0 {
+		return result, &FeaturesHealthError{Errors: failed}
+	}
+
+	return result, nil
+}
+
+func (c *Client) getFeaturesHealthBatch(
+	ctx context.Context,
+	keys []string,
+) (map[string]*FeatureHealth, []string, error) {
+	start := time.Now()
+	c.metrics.IncFeatureHealthRequest()
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	health, notFound, err := c.getFeaturesHealthWithRetries(ctx, keys)
+
+	c.metrics.ObserveFeatureHealthLatency(time.Since(start))
+
+	switch {
+	case errors.Is(err, errBulkHealthUnsupported):
+		if !c.bulkHealthUnsupported.Swap(true) {
+			c.logger.Info("bulk health endpoint not supported, using per-feature requests")
+		}
+	case err != nil:
+		c.metrics.IncFeatureHealthError("get_features_health_failed")
+		c.debug.recordError("health", strings.Join(keys, ","), err)
+		c.stats.markFailure(err)
+	default:
+		c.stats.markSuccess()
+	}
+
+	return health, notFound, err
+}
+
+func (c *Client) getFeaturesHealthWithRetries(
+	ctx context.Context,
+	keys []string,
+) (map[string]*FeatureHealth, []string, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= c.cfg.Retries; attempt++ {
+		if attempt > 0 {
+			delay := c.calculateBackoffDelay(attempt)
+			c.logger.Debug("retrying get features health after delay", "attempt", attempt, "delay", delay)
+
+			select {
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		resp, err := c.apiClient.GetFeaturesHealth(ctx, &api.FeaturesHealthRequest{FeatureKeys: keys})
+		if err == nil {
+			switch r := resp.(type) {
+			case *api.FeaturesHealthResponse:
+				health := make(map[string]*FeatureHealth, len(r.Features))
+				for i := range r.Features {
+					h := convertFeatureHealth(&r.Features[i])
+					health[h.FeatureKey] = h
+				}
+
+				return health, r.NotFound, nil
+			case *api.GetFeaturesHealthNotFound,
+				*api.GetFeaturesHealthMethodNotAllowed,
+				*api.GetFeaturesHealthNotImplemented:
+				return nil, nil, errBulkHealthUnsupported
+			case *api.ErrorBadRequest:
+				return nil, nil, ErrBadRequest
+			case *api.ErrorUnauthorized:
+				return nil, nil, ErrUnauthorized
+			case *api.ErrorInternalServerError:
+				return nil, nil, ErrInternalServerError
+			case *api.ErrorStatusCode:
+				return nil, nil, &APIError{
+					Code:       "get_features_health_failed",
+					Message:    fmt.Sprintf("unexpected features health status: %d", r.StatusCode),
+					StatusCode: r.StatusCode,
+				}
+			default:
+				return nil, nil, fmt.Errorf("unexpected response type: %T", resp)
+			}
+		}
+
+		lastErr = err
+
+		if !shouldRetry(err) {
+			c.logger.Debug("not retrying get features health due to error type", "error", err)
+			break
+		}
+
+		c.logger.Debug("retrying get features health due to error", "attempt", attempt, "error", err)
+	}
+
+	return nil, nil, lastErr
+}
+
+// isServerError reports whether err is a 5xx response. Such a failure of the
+// bulk endpoint is not permanent, but its batch is worth retrying per key.
+func isServerError(err error) bool {
+	if errors.Is(err, ErrInternalServerError) {
+		return true
+	}
+
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 500
+}
+
+// HealthSummary aggregates the health of several features.
+type HealthSummary struct {
+	Total int
+	// Unhealthy lists features that are disabled or auto-disabled.
+	Unhealthy    []string
+	AutoDisabled []string
+	// NearThreshold lists healthy features with a known error rate, closest
+	// to their auto-disable threshold first.
+	NearThreshold []*FeatureHealth
+}
+
+// SummarizeHealth summarizes the result of GetFeaturesHealth, keeping at most
+// top features in NearThreshold.
+func SummarizeHealth(health map[string]*FeatureHealth, top int) HealthSummary {
+	summary := HealthSummary{Total: len(health)}
+
+	for key, h := range health {
+		if h.AutoDisabled {
+			summary.AutoDisabled = append(summary.AutoDisabled, key)
+		}
+
+		if !h.Enabled || h.AutoDisabled {
+			summary.Unhealthy = append(summary.Unhealthy, key)
+
+			continue
+		}
+
+		if _, ok := h.ThresholdRatio(); ok {
+			summary.NearThreshold = append(summary.NearThreshold, h)
+		}
+	}
+
+	sort.Strings(summary.Unhealthy)
+	sort.Strings(summary.AutoDisabled)
+	sort.Slice(summary.NearThreshold, func(i, j int) bool {
+		ri, _ := summary.NearThreshold[i].ThresholdRatio()
+		rj, _ := summary.NearThreshold[j].ThresholdRatio()
+		if ri != rj {
+			return ri > rj
+		}
+
+		return summary.NearThreshold[i].FeatureKey < summary.NearThreshold[j].FeatureKey
+	})
+
+	if top >= 0 && len(summary.NearThreshold) > top {
+		summary.NearThreshold = summary.NearThreshold[:top]
+	}
+
+	return summary
+}
@@ -0,0 +1,188 @@
+package togglr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func healthBody(featureKey string, enabled, autoDisabled bool, errorRate, threshold float32) map[string]any {
+	return map[string]any{
+		"feature_key":     featureKey,
+		"environment_key": "prod",
+		"enabled":         enabled,
+		"auto_disabled":   autoDisabled,
+		"error_rate":      errorRate,
+		"threshold":       threshold,
+	}
+}
+
+func TestClientGetFeaturesHealthBulk(t *testing.T) {
+	var bulkCalls, singleCalls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/health", func(w http.ResponseWriter, r *http.Request) {
+		bulkCalls.Add(1)
+
+		var req struct {
+			FeatureKeys []string `json:"feature_keys"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		assert.Equal(t, []string{"a", "b", "missing"}, req.FeatureKeys)
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"features": []any{
+				healthBody("a", true, false, 0.1, 0.5),
+				healthBody("b", true, true, 0.6, 0.5),
+			},
+			"not_found": []string{"missing"},
+		})
+	})
+	mux.HandleFunc("GET /sdk/v1/features/{feature_key}/health", func(w http.ResponseWriter, _ *http.Request) {
+		singleCalls.Add(1)
+	})
+
+	client := newTestClient(t, mux)
+
+	health, err := client.GetFeaturesHealth(context.Background(), []string{"b", "a", "missing", "a"})
+
+	var healthErr *FeaturesHealthError
+	require.ErrorAs(t, err, &healthErr)
+	assert.Len(t, healthErr.Errors, 1)
+	assert.ErrorIs(t, err, ErrFeatureNotFound)
+
+	require.Len(t, health, 2)
+	assert.False(t, health["a"].AutoDisabled)
+	assert.True(t, health["b"].AutoDisabled)
+	assert.Equal(t, int32(1), bulkCalls.Load())
+	assert.Zero(t, singleCalls.Load())
+}
+
+func TestClientGetFeaturesHealthFallback(t *testing.T) {
+	var singleCalls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sdk/v1/features/{feature_key}/health", func(w http.ResponseWriter, r *http.Request) {
+		singleCalls.Add(1)
+
+		key := r.PathValue("feature_key")
+		if key == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, healthBody(key, true, false, 0.1, 0.5))
+	})
+
+	client := newTestClient(t, mux, WithRetries(0))
+
+	health, err := client.GetFeaturesHealth(context.Background(), []string{"a", "b", "broken"})
+
+	var healthErr *FeaturesHealthError
+	require.ErrorAs(t, err, &healthErr)
+	assert.Contains(t, healthErr.Errors, "broken")
+	assert.Len(t, health, 2)
+	assert.True(t, client.bulkHealthUnsupported.Load())
+	assert.Equal(t, int32(3), singleCalls.Load())
+
+	// The bulk endpoint is not tried again.
+	health, err = client.GetFeaturesHealth(context.Background(), []string{"a"})
+	require.NoError(t, err)
+	assert.Len(t, health, 1)
+	assert.Equal(t, int32(4), singleCalls.Load())
+}
+
+func TestFeaturesHealthErrorUnwrap(t *testing.T) {
+	err := &FeaturesHealthError{Errors: map[string]error{"a": ErrUnauthorized}}
+
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.Equal(t, "failed to get health of 1 feature(s), a: unauthorized", err.Error())
+	assert.Equal(t, "failed to get health of features", (&FeaturesHealthError{}).Error())
+}
+
+func TestClientGetFeaturesHealthServerErrorFallback(t *testing.T) {
+	var bulkCalls, singleCalls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/health", func(w http.ResponseWriter, _ *http.Request) {
+		if bulkCalls.Add(1) == 1 {
+			writeJSON(w, http.StatusInternalServerError, map[string]any{"error": map[string]any{"message": "boom"}})
+
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"features": []any{healthBody("a", true, false, 0.1, 0.5)}})
+	})
+	mux.HandleFunc("GET /sdk/v1/features/{feature_key}/health", func(w http.ResponseWriter, r *http.Request) {
+		singleCalls.Add(1)
+		writeJSON(w, http.StatusOK, healthBody(r.PathValue("feature_key"), true, false, 0.1, 0.5))
+	})
+
+	client := newTestClient(t, mux, WithRetries(0))
+
+	health, err := client.GetFeaturesHealth(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Len(t, health, 2)
+	assert.Equal(t, int32(2), singleCalls.Load())
+	assert.False(t, client.bulkHealthUnsupported.Load(), "a 5xx must not disable the bulk endpoint")
+
+	health, err = client.GetFeaturesHealth(context.Background(), []string{"a"})
+	require.NoError(t, err)
+	assert.Len(t, health, 1)
+	assert.Equal(t, int32(2), bulkCalls.Load())
+	assert.Equal(t, int32(2), singleCalls.Load())
+}
+
+func TestClientGetFeaturesHealthNotImplemented(t *testing.T) {
+	var singleCalls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/health", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotImplemented)
+	})
+	mux.HandleFunc("GET /sdk/v1/features/{feature_key}/health", func(w http.ResponseWriter, r *http.Request) {
+		singleCalls.Add(1)
+		writeJSON(w, http.StatusOK, healthBody(r.PathValue("feature_key"), true, false, 0.1, 0.5))
+	})
+
+	client := newTestClient(t, mux, WithRetries(0))
+
+	health, err := client.GetFeaturesHealth(context.Background(), []string{"a"})
+	require.NoError(t, err)
+	assert.Len(t, health, 1)
+	assert.True(t, client.bulkHealthUnsupported.Load())
+}
+
+func TestSummarizeHealth(t *testing.T) {
+	health := func(key string, enabled, autoDisabled bool, errorRate, threshold float32) *FeatureHealth {
+		return &FeatureHealth{
+			FeatureKey:   key,
+			Enabled:      enabled,
+			AutoDisabled: autoDisabled,
+			ErrorRate:    &errorRate,
+			Threshold:    &threshold,
+		}
+	}
+
+	summary := SummarizeHealth(map[string]*FeatureHealth{
+		"calm":     health("calm", true, false, 0.01, 0.5),
+		"warm":     health("warm", true, false, 0.3, 0.5),
+		"hot":      health("hot", true, false, 0.45, 0.5),
+		"off":      health("off", false, false, 0, 0.5),
+		"tripped":  health("tripped", true, true, 0.7, 0.5),
+		"no_limit": {FeatureKey: "no_limit", Enabled: true},
+	}, 2)
+
+	assert.Equal(t, 6, summary.Total)
+	assert.Equal(t, []string{"off", "tripped"}, summary.Unhealthy)
+	assert.Equal(t, []string{"tripped"}, summary.AutoDisabled)
+	require.Len(t, summary.NearThreshold, 2)
+	assert.Equal(t, "hot", summary.NearThreshold[0].FeatureKey)
+	assert.Equal(t, "warm", summary.NearThreshold[1].FeatureKey)
+}
@@ -0,0 +1,455 @@
+// Package fingerprint computes stable hashes of evaluation contexts.
+//
+// Values are written in a canonical, type-tagged binary form:
+//
+//   - map keys are sorted, nested maps and slices are encoded recursively;
+//   - numerically equal integers and floats (int(5), uint8(5), float64(5),
+//     json.Number("5")) encode identically, -0 equals 0 and every NaN is
+//     the same value;
+//   - time.Time is encoded as its UTC instant, so the location is ignored;
+//   - pointers are dereferenced and nil pointers encode as nil;
+//   - other types are encoded through their JSON representation.
+//
+// The encoding is part of the public contract: it only changes together with
+// Version.
+package fingerprint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const Version = 2
+
+const (
+	tagNil    = 'z'
+	tagFalse  = 'f'
+	tagTrue   = 't'
+	tagInt    = 'i'
+	tagUint   = 'u'
+	tagFloat  = 'd'
+	tagNaN    = 'n'
+	tagString = 's'
+	tagBytes  = 'b'
+	tagTime   = 'T'
+	tagList   = 'l'
+	tagMap    = 'm'
+	tagOther  = 'x'
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+)
+
+// Fingerprint returns the hex encoded fingerprint of ctx.
+func Fingerprint(ctx map[string]any) string {
+	h := hasherPool.Get().(*Hasher)
+	defer hasherPool.Put(h)
+
+	h.Reset()
+	h.writeStringMap(ctx)
+
+	return h.Sum()
+}
+
+// Hasher computes a fingerprint over a stream of values.
+type Hasher struct {
+	h    hash.Hash
+	buf  []byte
+	keys []string
+}
+
+var hasherPool = sync.Pool{
+	New: func() any { return NewHasher() },
+}
+
+func NewHasher() *Hasher {
+	return &Hasher{
+		h:   sha256.New(),
+		buf: make([]byte, 0, 64),
+	}
+}
+
+func (h *Hasher) Reset() {
+	h.h.Reset()
+	h.buf = h.buf[:0]
+}
+
+// Write adds the canonical encoding of v to the hash.
+func (h *Hasher) Write(v any) {
+	h.writeValue(v)
+	h.flush()
+}
+
+// Sum returns the hex encoded fingerprint of everything written so far.
+func (h *Hasher) Sum() string {
+	h.flush()
+
+	var sum [sha256.Size]byte
+	h.h.Sum(sum[:0])
+
+	return hex.EncodeToString(sum[:16])
+}
+
+// Append appends the canonical encoding of v to dst.
+func Append(dst []byte, v any) []byte {
+	h := &Hasher{buf: dst}
+	h.writeValue(v)
+
+	return h.buf
+}
+
+func (h *Hasher) flush() {
+	if h.h == nil || len(h.buf) == 0 {
+		return
+	}
+
+	h.h.Write(h.buf)
+	h.buf = h.buf[:0]
+}
+
+func (h *Hasher) maybeFlush() {
+	if h.h != nil && len(h.buf) >= 512 {
+		h.flush()
+	}
+}
+
+func (h *Hasher) writeTag(tag byte) {
+	h.buf = append(h.buf, tag)
+}
+
+func (h *Hasher) writeLen(n int) {
+	h.buf = binary.AppendUvarint(h.buf, uint64(n))
+}
+
+func (h *Hasher) writeString(tag byte, s string) {
+	h.writeTag(tag)
+	h.writeLen(len(s))
+	h.buf = append(h.buf, s...)
+	h.maybeFlush()
+}
+
+func (h *Hasher) writeInt(v int64) {
+	h.writeTag(tagInt)
+	h.buf = binary.BigEndian.AppendUint64(h.buf, uint64(v))
+}
+
+func (h *Hasher) writeUint(v uint64) {
+	if v <= math.MaxInt64 {
+		h.writeInt(int64(v))
+
+		return
+	}
+
+	h.writeTag(tagUint)
+	h.buf = binary.BigEndian.AppendUint64(h.buf, v)
+}
+
+func (h *Hasher) writeFloat(f float64) {
+	switch {
+	case math.IsNaN(f):
+		h.writeTag(tagNaN)
+	case f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64:
+		h.writeInt(int64(f))
+	case f == math.Trunc(f) && f >= 0 && f < math.MaxUint64:
+		h.writeUint(uint64(f))
+	default:
+		h.writeTag(tagFloat)
+		h.buf = binary.BigEndian.AppendUint64(h.buf, math.Float64bits(f))
+	}
+}
+
+// writeNumber treats anything outside the int64 range as a float64, which is
+// how encoding/json formats such values in the first place.
+func (h *Hasher) writeNumber(n json.Number) {
+	s := string(n)
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		h.writeInt(i)
+
+		return
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		h.writeFloat(f)
+
+		return
+	}
+
+	h.writeString(tagString, s)
+}
+
+func (h *Hasher) writeTime(t time.Time) {
+	h.writeTag(tagTime)
+	h.buf = binary.BigEndian.AppendUint64(h.buf, uint64(t.Unix()))
+	h.buf = binary.BigEndian.AppendUint32(h.buf, uint32(t.Nanosecond()))
+}
+
+func (h *Hasher) writeStringMap(m map[string]any) {
+	h.writeTag(tagMap)
+	h.writeLen(len(m))
+
+	start := len(h.keys)
+	for k := range m {
+		h.keys = append(h.keys, k)
+	}
+	keys := h.keys[start:]
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		h.writeString(tagString, k)
+		h.writeValue(m[k])
+	}
+
+	clear(keys)
+	h.keys = h.keys[:start]
+}
+
+// writeValue handles the common concrete types without reflection.
+func (h *Hasher) writeValue(v any) {
+	switch x := v.(type) {
+	case nil:
+		h.writeTag(tagNil)
+	case bool:
+		if x {
+			h.writeTag(tagTrue)
+		} else {
+			h.writeTag(tagFalse)
+		}
+	case string:
+		h.writeString(tagString, x)
+	case int:
+		h.writeInt(int64(x))
+	case int8:
+		h.writeInt(int64(x))
+	case int16:
+		h.writeInt(int64(x))
+	case int32:
+		h.writeInt(int64(x))
+	case int64:
+		h.writeInt(x)
+	case uint:
+		h.writeUint(uint64(x))
+	case uint8:
+		h.writeUint(uint64(x))
+	case uint16:
+		h.writeUint(uint64(x))
+	case uint32:
+		h.writeUint(uint64(x))
+	case uint64:
+		h.writeUint(x)
+	case float32:
+		h.writeFloat(float64(x))
+	case float64:
+		h.writeFloat(x)
+	case json.Number:
+		h.writeNumber(x)
+	case time.Time:
+		h.writeTime(x)
+	case []byte:
+		if x == nil {
+			h.writeTag(tagNil)
+		} else {
+			h.writeString(tagBytes, string(x))
+		}
+	case map[string]any:
+		if x == nil {
+			h.writeTag(tagNil)
+		} else {
+			h.writeStringMap(x)
+		}
+	case []any:
+		if x == nil {
+			h.writeTag(tagNil)
+
+			break
+		}
+		h.writeTag(tagList)
+		h.writeLen(len(x))
+		for _, e := range x {
+			h.writeValue(e)
+		}
+	case []string:
+		if x == nil {
+			h.writeTag(tagNil)
+
+			break
+		}
+		h.writeTag(tagList)
+		h.writeLen(len(x))
+		for _, e := range x {
+			h.writeString(tagString, e)
+		}
+	default:
+		h.writeReflect(reflect.ValueOf(v))
+	}
+
+	h.maybeFlush()
+}
+
+func (h *Hasher) writeReflect(rv reflect.Value) {
+	if !rv.IsValid() {
+		h.writeTag(tagNil)
+
+		return
+	}
+
+	switch rv.Type() {
+	case timeType:
+		h.writeTime(rv.Interface().(time.Time))
+
+		return
+	case jsonNumberType:
+		h.writeNumber(json.Number(rv.String()))
+
+		return
+	}
+
+	if rv.Type().Implements(jsonMarshalerType) && (rv.Kind() != reflect.Pointer || !rv.IsNil()) {
+		h.writeJSON(rv.Interface())
+
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			h.writeTag(tagNil)
+
+			return
+		}
+		h.writeReflect(rv.Elem())
+	case reflect.Bool:
+		h.writeValue(rv.Bool())
+	case reflect.String:
+		h.writeString(tagString, rv.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		h.writeInt(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		h.writeUint(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		h.writeFloat(rv.Float())
+	case reflect.Slice:
+		if rv.IsNil() {
+			h.writeTag(tagNil)
+
+			return
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			h.writeString(tagBytes, string(rv.Bytes()))
+
+			return
+		}
+		h.writeList(rv)
+	case reflect.Array:
+		h.writeList(rv)
+	case reflect.Map:
+		if rv.IsNil() {
+			h.writeTag(tagNil)
+
+			return
+		}
+		h.writeMap(rv)
+	default:
+		h.writeJSON(rv.Interface())
+	}
+}
+
+func (h *Hasher) writeList(rv reflect.Value) {
+	h.writeTag(tagList)
+	h.writeLen(rv.Len())
+
+	for i := 0; i < rv.Len(); i++ {
+		h.writeReflect(rv.Index(i))
+	}
+}
+
+func (h *Hasher) writeMap(rv reflect.Value) {
+	type kv struct {
+		key   string
+		value reflect.Value
+	}
+
+	entries := make([]kv, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		entries = append(entries, kv{key: mapKeyString(iter.Key()), value: iter.Value()})
+	}
+
+	slices.SortFunc(entries, func(a, b kv) int {
+		switch {
+		case a.key < b.key:
+			return -1
+		case a.key > b.key:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	h.writeTag(tagMap)
+	h.writeLen(len(entries))
+
+	for _, e := range entries {
+		h.writeString(tagString, e.key)
+		h.writeReflect(e.value)
+	}
+}
+
+// mapKeyString mirrors how encoding/json turns map keys into object keys.
+func mapKeyString(k reflect.Value) string {
+	for k.Kind() == reflect.Interface && !k.IsNil() {
+		k = k.Elem()
+	}
+
+	switch k.Kind() {
+	case reflect.String:
+		return k.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	}
+
+	if raw, err := json.Marshal(k.Interface()); err == nil {
+		return string(raw)
+	}
+
+	return k.Type().String()
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// writeJSON encodes v through its JSON representation, which is what the
+// server receives. Values that cannot be marshalled are identified by type.
+func (h *Hasher) writeJSON(v any) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		h.writeString(tagOther, reflect.TypeOf(v).String())
+
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		h.writeString(tagOther, reflect.TypeOf(v).String())
+
+		return
+	}
+
+	h.writeValue(decoded)
+}
@@ -0,0 +1,227 @@
+package fingerprint
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendVectors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"nil", nil, "7a"},
+		{"true", true, "74"},
+		{"int", 5, "690000000000000005"},
+		{"negative int", -1, "69ffffffffffffffff"},
+		{"float", 1.5, "643ff8000000000000"},
+		{"nan", math.NaN(), "6e"},
+		{"string", "a", "730161"},
+		{"time", time.Unix(1700000000, 5), "54000000006553f10000000005"},
+		{"list", []any{1, "x"}, "6c02690000000000000001730178"},
+		{"map", map[string]any{"b": 1, "a": nil}, "6d027301617a730162690000000000000001"},
+		{"max uint64", uint64(math.MaxUint64), "75ffffffffffffffff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hex.EncodeToString(Append(nil, tt.value)))
+		})
+	}
+}
+
+func TestFingerprintVectors(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  map[string]any
+		want string
+	}{
+		{"empty", nil, "187355b101e4d1f66c7948f93d109b63"},
+		{"flat", map[string]any{"user.id": "123", "country_code": "US", "age": 30}, "4a4aa74fb66fcc0c73aad17b17a1e031"},
+		{"nested", map[string]any{"tags": []any{"a", "b"}, "meta": map[string]any{"x": 1.25}}, "3b2e8d96c51d11237f461d658c07bdba"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Fingerprint(tt.ctx))
+		})
+	}
+}
+
+type level string
+
+type point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func TestFingerprintEquivalence(t *testing.T) {
+	five := 5
+	moscow := time.FixedZone("MSK", 3*60*60)
+	instant := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	tests := []struct {
+		name string
+		a, b any
+	}{
+		{"int and float64", 5, 5.0},
+		{"int and uint8", 5, uint8(5)},
+		{"int and json.Number", 5, json.Number("5")},
+		{"float and json.Number", 1.25, json.Number("1.25")},
+		{"float32 and float64", float32(0.5), 0.5},
+		{"negative zero", math.Copysign(0, -1), 0},
+		{"nan payloads", math.NaN(), math.Float64frombits(0x7ff8000000000001)},
+		{"time locations", instant, instant.In(moscow)},
+		{"pointer", &five, 5},
+		{"named string", level("debug"), "debug"},
+		{"typed map", map[string]string{"a": "1"}, map[string]any{"a": "1"}},
+		{"int keyed map", map[int]bool{1: true}, map[string]any{"1": true}},
+		{"typed slice", []int{1, 2}, []any{1.0, 2.0}},
+		{"array", [2]string{"a", "b"}, []string{"a", "b"}},
+		{"struct", point{X: 1, Y: 2}, map[string]any{"x": 1, "y": 2}},
+		{"nil slice", []string(nil), nil},
+		{"nil map", map[string]any(nil), nil},
+		{"nil pointer", (*int)(nil), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, Append(nil, tt.a), Append(nil, tt.b))
+			assert.Equal(t,
+				Fingerprint(map[string]any{"k": tt.a}),
+				Fingerprint(map[string]any{"k": tt.b}))
+		})
+	}
+}
+
+func TestFingerprintDistinct(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b map[string]any
+	}{
+		{"string and number", map[string]any{"k": "5"}, map[string]any{"k": 5}},
+		{"string and bool", map[string]any{"k": "true"}, map[string]any{"k": true}},
+		{"nil and missing", map[string]any{"k": nil}, map[string]any{}},
+		{"empty list and nil", map[string]any{"k": []any{}}, map[string]any{"k": nil}},
+		{"list order", map[string]any{"k": []any{1, 2}}, map[string]any{"k": []any{2, 1}}},
+		{"key value boundary", map[string]any{"ab": "c"}, map[string]any{"a": "bc"}},
+		{"nested vs flat", map[string]any{"a": map[string]any{"b": 1}}, map[string]any{"a.b": 1}},
+		{"large floats", map[string]any{"k": 1e300}, map[string]any{"k": math.Inf(1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotEqual(t, Fingerprint(tt.a), Fingerprint(tt.b))
+		})
+	}
+}
+
+func TestHasherMatchesFingerprint(t *testing.T) {
+	ctx := map[string]any{"user.id": "123", "tags": []any{"a", "b"}}
+
+	h := NewHasher()
+	h.Write(ctx)
+	assert.Equal(t, Fingerprint(ctx), h.Sum())
+
+	h.Reset()
+	h.Write(ctx)
+	assert.Equal(t, Fingerprint(ctx), h.Sum())
+}
+
+func TestFingerprintLargeValues(t *testing.T) {
+	big := bytes.Repeat([]byte("x"), 4096)
+
+	a := Fingerprint(map[string]any{"k": string(big)})
+	big[4095] = 'y'
+	b := Fingerprint(map[string]any{"k": string(big)})
+
+	assert.NotEqual(t, a, b)
+}
+
+// FuzzFingerprint checks that a context fingerprints the same as its JSON
+// round trip, i.e. as the server sees it, and that integral floats match
+// their integer form.
+func FuzzFingerprint(f *testing.F) {
+	f.Add("user.id", "123", int64(30), 1.5, true)
+	f.Add("", "", int64(0), math.Copysign(0, -1), false)
+	f.Add("country_code", "US", int64(math.MaxInt64), 9.223372036854776e18, true)
+	f.Add("age", "\x00\xff", int64(-1), -1.0, false)
+	f.Add("k", "ü", int64(1<<53), 1e-300, true)
+
+	f.Fuzz(func(t *testing.T, key, s string, i int64, fl float64, b bool) {
+		if math.IsNaN(fl) || math.IsInf(fl, 0) {
+			return
+		}
+
+		ctx := map[string]any{
+			key:      s,
+			"int":    i,
+			"float":  fl,
+			"bool":   b,
+			"list":   []any{i, fl, s},
+			"nested": map[string]any{key: fl},
+		}
+
+		raw, err := json.Marshal(ctx)
+		require.NoError(t, err)
+
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+
+		var decoded map[string]any
+		require.NoError(t, dec.Decode(&decoded))
+
+		// encoding/json replaces invalid UTF-8, so only valid strings survive.
+		if utf8.ValidString(key) && utf8.ValidString(s) {
+			assert.Equal(t, Fingerprint(ctx), Fingerprint(decoded))
+		}
+
+		if fl == math.Trunc(fl) && fl >= math.MinInt64 && fl < math.MaxInt64 {
+			assert.Equal(t, Append(nil, int64(fl)), Append(nil, fl))
+		}
+	})
+}
+
+func BenchmarkFingerprint(b *testing.B) {
+	cases := map[string]map[string]any{
+		"small": {
+			"user.id": "123",
+		},
+		"typical": {
+			"user.id":         "user-123456",
+			"user.email":      "user@example.com",
+			"user.anonymous":  false,
+			"country_code":    "US",
+			"device_type":     "mobile",
+			"os":              "iOS",
+			"os_version":      "17.2",
+			"browser":         "Safari",
+			"browser_version": "17.0",
+			"age":             30,
+			"ip":              "203.0.113.10",
+		},
+		"nested": {
+			"user.id": "123",
+			"tags":    []any{"beta", "staff", "eu"},
+			"meta":    map[string]any{"plan": "pro", "seats": 25, "ratio": 0.75},
+		},
+	}
+
+	for name, ctx := range cases {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = Fingerprint(ctx)
+			}
+		})
+	}
+}
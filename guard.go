@@ -0,0 +1,86 @@
+package togglr
+
+import (
+	"context"
+)
+
+// Guard runs enabledFn when featureKey is enabled for req and fallbackFn
+// otherwise. A successful enabledFn is tracked as a success of the evaluated
+// variant; a failing one is followed by fallbackFn, reported with
+// ReportError and tracked as a failure. fallbackFn may be nil.
+//
+// An enabled default from Config.Defaults or a stale snapshot entry also
+// routes to enabledFn, for example while the server is down. Outcomes are
+// only sent when the server enabled the feature, directly or through the
+// cache.
+//
+// Outcomes are sent in the background, so that neither the caller nor the
+// fallback waits for the server; Close waits for outcomes still being sent.
+// Reporting and tracking errors are logged, not returned.
+func (c *Client) Guard(
+	ctx context.Context,
+	featureKey string,
+	req RequestContext,
+	enabledFn, fallbackFn func(context.Context) error,
+) error {
+	res := c.EvaluateWithContext(ctx, featureKey, req)
+	if !res.Enabled() || res.failed() {
+		return runFallback(ctx, fallbackFn, nil)
+	}
+
+	variant := res.Value()
+
+	err := enabledFn(ctx)
+
+	switch {
+	case !res.fromServer():
+		c.logger.Debug("feature not enabled by the server, outcome not sent",
+			"feature_key", featureKey, "source", res.Source(), "error", err)
+	case err == nil:
+		event := NewTrackEvent(variant, EventTypeSuccess).WithContexts(req)
+		c.sendOutcome(ctx, featureKey, nil, event)
+	default:
+		// The report and event are built here, so that the stack trace is the
+		// caller's and later changes to req do not leak into them.
+		report := NewErrorReportFromError(err).WithContext("variant", variant)
+		event := NewTrackEvent(variant, EventTypeFailure).WithContexts(req)
+		c.sendOutcome(ctx, featureKey, report, event)
+	}
+
+	if err != nil {
+		return runFallback(ctx, fallbackFn, err)
+	}
+
+	return nil
+}
+
+func runFallback(ctx context.Context, fallbackFn func(context.Context) error, cause error) error {
+	if fallbackFn == nil {
+		return cause
+	}
+
+	return fallbackFn(ctx)
+}
+
+// sendOutcome reports report, if any, and tracks event in the background.
+// Outcomes are sent even if ctx was cancelled, which is often why the
+// guarded code failed in the first place.
+func (c *Client) sendOutcome(ctx context.Context, featureKey string, report *ErrorReport, event *TrackEvent) {
+	ctx = context.WithoutCancel(ctx)
+
+	started := c.goBackground(func() {
+		if report != nil {
+			if err := c.ReportError(ctx, featureKey, report); err != nil {
+				c.logger.Warn("failed to report error", "feature_key", featureKey, "error", err)
+			}
+		}
+
+		if err := c.TrackEvent(ctx, featureKey, event); err != nil {
+			c.logger.Warn("failed to track outcome",
+				"feature_key", featureKey, "event_type", event.EventType, "error", err)
+		}
+	})
+	if !started {
+		c.logger.Debug("client closed, outcome not sent", "feature_key", featureKey)
+	}
+}
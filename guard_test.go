@@ -0,0 +1,225 @@
+package togglr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingServer struct {
+	mu      sync.Mutex
+	tracks  []map[string]any
+	reports []map[string]any
+}
+
+func (s *recordingServer) handler(calls *atomic.Int32, enabled bool, value string) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(calls, enabled, value))
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/track", func(w http.ResponseWriter, r *http.Request) {
+		s.record(&s.tracks, r)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/report-error", func(w http.ResponseWriter, r *http.Request) {
+		s.record(&s.reports, r)
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	return mux
+}
+
+func (s *recordingServer) record(into *[]map[string]any, r *http.Request) {
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	*into = append(*into, body)
+}
+
+func TestClientGuard(t *testing.T) {
+	var calls atomic.Int32
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name          string
+		enabled       bool
+		enabledErr    error
+		fallbackErr   error
+		wantErr       error
+		wantEnabled   bool
+		wantFallback  bool
+		wantTrack     string
+		wantErrorType string
+	}{
+		{
+			name:         "disabled runs fallback",
+			enabled:      false,
+			wantFallback: true,
+		},
+		{
+			name:        "enabled success is tracked",
+			enabled:     true,
+			wantEnabled: true,
+			wantTrack:   "success",
+		},
+		{
+			name:          "enabled failure reports and falls back",
+			enabled:       true,
+			enabledErr:    fmt.Errorf("load: %w", context.DeadlineExceeded),
+			wantEnabled:   true,
+			wantFallback:  true,
+			wantTrack:     "failure",
+			wantErrorType: "timeout",
+		},
+		{
+			name:          "fallback error is returned",
+			enabled:       true,
+			enabledErr:    &fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist},
+			fallbackErr:   errBoom,
+			wantErr:       errBoom,
+			wantEnabled:   true,
+			wantFallback:  true,
+			wantTrack:     "failure",
+			wantErrorType: "*fs.PathError",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &recordingServer{}
+			client := newTestClient(t, srv.handler(&calls, tt.enabled, "B"))
+
+			var ranEnabled, ranFallback bool
+			err := client.Guard(context.Background(), "new_flow", NewContext().WithUserID("u1"),
+				func(context.Context) error {
+					ranEnabled = true
+
+					return tt.enabledErr
+				},
+				func(context.Context) error {
+					ranFallback = true
+
+					return tt.fallbackErr
+				},
+			)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantEnabled, ranEnabled)
+			assert.Equal(t, tt.wantFallback, ranFallback)
+
+			// Close waits for outcomes sent in the background.
+			require.NoError(t, client.Close())
+
+			if tt.wantTrack == "" {
+				assert.Empty(t, srv.tracks)
+			} else {
+				require.Len(t, srv.tracks, 1)
+				assert.Equal(t, tt.wantTrack, srv.tracks[0]["event_type"])
+				assert.Equal(t, "B", srv.tracks[0]["variant_key"])
+				assert.Equal(t, map[string]any{AttrUserID: "u1"}, srv.tracks[0]["context"])
+			}
+
+			if tt.wantErrorType == "" {
+				assert.Empty(t, srv.reports)
+			} else {
+				require.Len(t, srv.reports, 1)
+				assert.Equal(t, tt.wantErrorType, srv.reports[0]["error_type"])
+			}
+		})
+	}
+}
+
+func TestClientGuardWithoutFallback(t *testing.T) {
+	var calls atomic.Int32
+	srv := &recordingServer{}
+	client := newTestClient(t, srv.handler(&calls, true, "B"))
+
+	errBoom := errors.New("boom")
+	err := client.Guard(context.Background(), "new_flow", NewContext(),
+		func(context.Context) error { return errBoom }, nil)
+
+	assert.ErrorIs(t, err, errBoom)
+	require.NoError(t, client.Close())
+	require.Len(t, srv.reports, 1)
+	assert.Equal(t, "error", srv.reports[0]["error_type"])
+}
+
+func TestClientGuardDefaultNotTracked(t *testing.T) {
+	var calls atomic.Int32
+	srv := &recordingServer{}
+	mux := srv.handler(&calls, true, "B")
+	mux.HandleFunc("POST /sdk/v1/features/new_flow/evaluate", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	client := newTestClient(t, mux,
+		WithRetries(0),
+		WithDefaults(map[string]DefaultValue{"new_flow": {Enabled: true, Value: "B"}}),
+	)
+
+	var ran, fellBack bool
+	err := client.Guard(context.Background(), "new_flow", NewContext(),
+		func(context.Context) error {
+			ran = true
+
+			return errors.New("boom")
+		},
+		func(context.Context) error {
+			fellBack = true
+
+			return nil
+		})
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	assert.True(t, ran, "an enabled default routes to the new path")
+	assert.True(t, fellBack)
+	assert.Empty(t, srv.tracks)
+	assert.Empty(t, srv.reports)
+}
+
+func TestClientGuardDoesNotWaitForOutcomes(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	srv := &recordingServer{}
+	mux := http.NewServeMux()
+	mux.Handle("/sdk/v1/features/{feature_key}/", srv.handler(&calls, true, "B"))
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/track", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		srv.record(&srv.tracks, r)
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	client := newTestClient(t, mux)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Guard(context.Background(), "new_flow", NewContext(),
+			func(context.Context) error { return errors.New("boom") },
+			func(context.Context) error { return nil })
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Guard waited for the outcome to be tracked")
+	}
+
+	close(release)
+	require.NoError(t, client.Close())
+	assert.Len(t, srv.tracks, 1)
+	assert.Len(t, srv.reports, 1)
+}
@@ -0,0 +1,188 @@
+package togglr
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxHealthGuardKeys bounds the number of features whose health is tracked.
+	// Features evaluated beyond the limit are not short-circuited.
+	maxHealthGuardKeys = 1024
+
+	// healthGuardIdleCycles is the number of refresh cycles without an
+	// evaluation after which a feature is no longer tracked.
+	healthGuardIdleCycles = 3
+)
+
+// healthGuard keeps the auto-disable state of every feature evaluated by a
+// health-aware client. States are refreshed in the background; features seen
+// for the first time are checked right away.
+type healthGuard struct {
+	mu     sync.RWMutex
+	states map[string]*guardState
+	fresh  map[string]struct{}
+	kick   chan struct{}
+}
+
+type guardState struct {
+	disabled bool
+	idle     int
+	seen     atomic.Bool
+}
+
+func newHealthGuard() *healthGuard {
+	return &healthGuard{
+		states: make(map[string]*guardState),
+		fresh:  make(map[string]struct{}),
+		kick:   make(chan struct{}, 1),
+	}
+}
+
+// autoDisabled reports whether featureKey is known to be auto-disabled and
+// starts tracking it if it is not tracked yet.
+func (g *healthGuard) autoDisabled(featureKey string) bool {
+	g.mu.RLock()
+	state, ok := g.states[featureKey]
+	var disabled bool
+	if ok {
+		state.seen.Store(true)
+		disabled = state.disabled
+	}
+	g.mu.RUnlock()
+
+	if ok {
+		return disabled
+	}
+
+	g.mu.Lock()
+	if _, ok := g.states[featureKey]; !ok {
+		if len(g.states) >= maxHealthGuardKeys {
+			g.mu.Unlock()
+
+			return false
+		}
+		state := &guardState{}
+		state.seen.Store(true)
+		g.states[featureKey] = state
+		g.fresh[featureKey] = struct{}{}
+	}
+	g.mu.Unlock()
+
+	select {
+	case g.kick <- struct{}{}:
+	default:
+	}
+
+	return false
+}
+
+// keys returns every tracked feature and stops tracking the ones that were not
+// evaluated for healthGuardIdleCycles calls.
+func (g *healthGuard) keys() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	keys := make([]string, 0, len(g.states))
+	for k, state := range g.states {
+		if state.seen.Swap(false) {
+			state.idle = 0
+		} else if state.idle++; state.idle >= healthGuardIdleCycles {
+			delete(g.states, k)
+			delete(g.fresh, k)
+
+			continue
+		}
+		keys = append(keys, k)
+	}
+	clear(g.fresh)
+
+	return keys
+}
+
+// freshKeys returns the features that started being tracked since the last
+// call.
+func (g *healthGuard) freshKeys() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	keys := make([]string, 0, len(g.fresh))
+	for k := range g.fresh {
+		keys = append(keys, k)
+	}
+	clear(g.fresh)
+
+	return keys
+}
+
+// update stores the new state and returns the previous one. It reports false
+// when the feature is no longer tracked.
+func (g *healthGuard) update(featureKey string, disabled bool) (prev, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, ok := g.states[featureKey]
+	if !ok {
+		return false, false
+	}
+
+	prev = state.disabled
+	state.disabled = disabled
+
+	return prev, true
+}
+
+func (c *Client) runHealthGuardLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.HealthRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.refreshHealthStates(c.guard.keys())
+		case <-c.guard.kick:
+			c.refreshHealthStates(c.guard.freshKeys())
+		}
+	}
+}
+
+func (c *Client) refreshHealthStates(keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	ctx, cancel := c.doneContext()
+	defer cancel()
+
+	forEachLimited(len(keys), 4, func(i int) {
+		featureKey := keys[i]
+
+		health, err := c.GetFeatureHealth(ctx, featureKey)
+		if err != nil {
+			c.logger.Debug("failed to refresh feature health", "feature_key", featureKey, "error", err)
+
+			return
+		}
+
+		disabled := health.AutoDisabled
+		if prev, ok := c.guard.update(featureKey, disabled); !ok || prev == disabled {
+			return
+		}
+
+		if c.cache != nil {
+			c.cache.DeleteFeature(featureKey)
+		}
+
+		if disabled {
+			c.logger.Warn("feature auto-disabled, evaluations short-circuited", "feature_key", featureKey)
+			incFeatureAutoDisabled(c.metrics, featureKey)
+		} else {
+			c.logger.Info("feature re-enabled after auto-disable", "feature_key", featureKey)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package togglr
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientHealthAwareEvaluation(t *testing.T) {
+	var evaluations atomic.Int32
+	var autoDisabled atomic.Bool
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&evaluations, true, "on"))
+	mux.HandleFunc("GET /sdk/v1/features/{feature_key}/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"feature_key":     r.PathValue("feature_key"),
+			"environment_key": "prod",
+			"enabled":         true,
+			"auto_disabled":   autoDisabled.Load(),
+		})
+	})
+
+	metrics := &testMetrics{}
+	client := newTestClient(t, mux,
+		WithMetrics(metrics),
+		WithCache(10, time.Minute),
+		WithHealthAwareEvaluation(10*time.Millisecond),
+	)
+
+	req := NewContext().WithUserID("u1")
+	res := client.Evaluate("new_ui", req)
+	assert.True(t, res.Enabled())
+	assert.Equal(t, 1, client.cache.Size())
+
+	autoDisabled.Store(true)
+
+	assert.Eventually(t, func() bool {
+		res := client.Evaluate("new_ui", req)
+
+		return res.Source() == SourceHealth
+	}, time.Second, 5*time.Millisecond)
+
+	res = client.Evaluate("new_ui", req)
+	assert.NoError(t, res.Err())
+	assert.True(t, res.Found())
+	assert.False(t, res.Enabled())
+	assert.Equal(t, 0, client.cache.Size())
+	assert.Equal(t, int32(1), metrics.autoDisabled.Load())
+
+	autoDisabled.Store(false)
+
+	assert.Eventually(t, func() bool {
+		res := client.Evaluate("new_ui", req)
+
+		return res.Enabled() && res.Source() == SourceServer
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestClientHealthGuardPollsOnlyNewFeatures(t *testing.T) {
+	var evaluations atomic.Int32
+	var mu sync.Mutex
+	polls := make(map[string]int)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&evaluations, true, "on"))
+	mux.HandleFunc("GET /sdk/v1/features/{feature_key}/health", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls[r.PathValue("feature_key")]++
+		mu.Unlock()
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"feature_key":     r.PathValue("feature_key"),
+			"environment_key": "prod",
+			"enabled":         true,
+			"auto_disabled":   false,
+		})
+	})
+
+	client := newTestClient(t, mux, WithHealthAwareEvaluation(time.Hour))
+
+	pollsOf := func(featureKey string) int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return polls[featureKey]
+	}
+
+	req := NewContext().WithUserID("u1")
+	for _, featureKey := range []string{"a", "b", "c"} {
+		client.Evaluate(featureKey, req)
+		assert.Eventually(t, func() bool { return pollsOf(featureKey) == 1 }, time.Second, 5*time.Millisecond)
+	}
+
+	client.Evaluate("a", req)
+	time.Sleep(20 * time.Millisecond)
+
+	assert.Equal(t, 1, pollsOf("a"))
+	assert.Equal(t, 1, pollsOf("b"))
+	assert.Equal(t, 1, pollsOf("c"))
+}
+
+func TestHealthGuardBounded(t *testing.T) {
+	g := newHealthGuard()
+
+	for i := range maxHealthGuardKeys + 10 {
+		g.autoDisabled(fmt.Sprintf("feature_%d", i))
+	}
+	assert.Len(t, g.states, maxHealthGuardKeys)
+	assert.Len(t, g.freshKeys(), maxHealthGuardKeys)
+	assert.Empty(t, g.freshKeys())
+
+	for range healthGuardIdleCycles {
+		g.autoDisabled("feature_0")
+		g.keys()
+	}
+	assert.Equal(t, []string{"feature_0"}, g.keys())
+
+	_, ok := g.update("feature_1", true)
+	assert.False(t, ok)
+	assert.Empty(t, g.disabledKeys())
+}
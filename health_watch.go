@@ -0,0 +1,96 @@
+package togglr
+
+import (
+	"context"
+	mathrand "math/rand/v2"
+	"time"
+)
+
+const healthWatchMaxBackoff = 16
+
+// WatchHealth polls the health of every key each interval until ctx is done
+// or the client is closed. fn is called with a nil old value on the first
+// successful poll of a key, and then whenever Enabled or AutoDisabled change
+// or ErrorRate crosses Config.HealthWatchThreshold of Threshold in either
+// direction. Polls of a key that fail are retried with exponential backoff.
+//
+// fn may be called concurrently for different keys. After Close, WatchHealth
+// does nothing.
+func (c *Client) WatchHealth(
+	ctx context.Context,
+	keys []string,
+	interval time.Duration,
+	fn func(old, new *FeatureHealth),
+) {
+	if interval <= 0 {
+		interval = DefaultConfig("").HealthRefreshInterval
+	}
+
+	for _, featureKey := range keys {
+		if !c.goBackground(func() { c.watchHealth(ctx, featureKey, interval, fn) }) {
+			return
+		}
+	}
+}
+
+func (c *Client) watchHealth(
+	ctx context.Context,
+	featureKey string,
+	interval time.Duration,
+	fn func(old, new *FeatureHealth),
+) {
+	backoff := Backoff{BaseDelay: interval, MaxDelay: healthWatchMaxBackoff * interval, Factor: 2}
+
+	var last *FeatureHealth
+	failures := 0
+
+	for {
+		delay := interval
+
+		health, err := c.GetFeatureHealth(ctx, featureKey)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return
+			}
+			failures++
+			delay = backoffDelay(backoff, failures+1)
+			c.logger.Debug("health watch poll failed", "feature_key", featureKey, "error", err, "retry_in", delay)
+		case last == nil || c.healthChanged(last, health):
+			failures = 0
+			fn(last, health)
+			last = health
+		default:
+			failures = 0
+			last = health
+		}
+
+		timer := time.NewTimer(delay + mathrand.N(delay/10+1))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-c.done:
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) healthChanged(old, new *FeatureHealth) bool {
+	return old.Enabled != new.Enabled ||
+		old.AutoDisabled != new.AutoDisabled ||
+		nearThreshold(old, c.cfg.HealthWatchThreshold) != nearThreshold(new, c.cfg.HealthWatchThreshold)
+}
+
+// nearThreshold reports whether the error rate has reached fraction of the
+// auto-disable threshold.
+func nearThreshold(h *FeatureHealth, fraction float64) bool {
+	ratio, ok := h.ThresholdRatio()
+
+	return ok && ratio >= fraction
+}
@@ -0,0 +1,126 @@
+package togglr
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type healthTransition struct {
+	old, new *FeatureHealth
+}
+
+func TestClientWatchHealth(t *testing.T) {
+	var autoDisabled atomic.Bool
+	var errorRate atomic.Value
+	errorRate.Store(float32(0.1))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sdk/v1/features/{feature_key}/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"feature_key":     r.PathValue("feature_key"),
+			"environment_key": "prod",
+			"enabled":         true,
+			"auto_disabled":   autoDisabled.Load(),
+			"error_rate":      errorRate.Load(),
+			"threshold":       0.5,
+		})
+	})
+
+	client := newTestClient(t, mux)
+
+	var mu sync.Mutex
+	var transitions []healthTransition
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client.WatchHealth(ctx, []string{"new_ui"}, 5*time.Millisecond, func(old, new *FeatureHealth) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		transitions = append(transitions, healthTransition{old, new})
+	})
+
+	waitFor := func(n int) []healthTransition {
+		t.Helper()
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return len(transitions) >= n
+		}, time.Second, 5*time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]healthTransition(nil), transitions...)
+	}
+
+	got := waitFor(1)
+	assert.Nil(t, got[0].old)
+	assert.Equal(t, "new_ui", got[0].new.FeatureKey)
+
+	// Crossing 80% of the threshold fires, staying above it does not.
+	errorRate.Store(float32(0.45))
+	got = waitFor(2)
+	assert.InDelta(t, 0.1, *got[1].old.ErrorRate, 1e-6)
+	assert.InDelta(t, 0.45, *got[1].new.ErrorRate, 1e-6)
+
+	errorRate.Store(float32(0.48))
+	time.Sleep(30 * time.Millisecond)
+
+	autoDisabled.Store(true)
+	got = waitFor(3)
+	assert.Len(t, got, 3)
+	assert.False(t, got[2].old.AutoDisabled)
+	assert.True(t, got[2].new.AutoDisabled)
+}
+
+func TestClientWatchHealthBackoff(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sdk/v1/features/{feature_key}/health", func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	client := newTestClient(t, mux, WithRetries(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client.WatchHealth(ctx, []string{"new_ui"}, 10*time.Millisecond, func(_, _ *FeatureHealth) {
+		t.Error("unexpected callback")
+	})
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	// Without backoff this would be about 20 polls.
+	assert.Less(t, calls.Load(), int32(8))
+	assert.Greater(t, calls.Load(), int32(1))
+}
+
+func TestClientWatchHealthAfterClose(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sdk/v1/features/{feature_key}/health", func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+	})
+
+	client := newTestClient(t, mux)
+	require.NoError(t, client.Close())
+
+	client.WatchHealth(context.Background(), []string{"a", "b"}, time.Millisecond, func(_, _ *FeatureHealth) {})
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Zero(t, calls.Load())
+}
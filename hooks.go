@@ -0,0 +1,129 @@
+package togglr
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+type Operation string
+
+const (
+	OperationEvaluate    Operation = "evaluate"
+	OperationTrack       Operation = "track"
+	OperationReportError Operation = "report_error"
+	OperationHealth      Operation = "health"
+)
+
+// HookContext describes the operation a hook runs for.
+type HookContext struct {
+	Operation Operation
+	// FeatureKey is empty for GetFeaturesHealth.
+	FeatureKey string
+	// RequestContext holds the evaluation context, the track event context or
+	// the error report context, and the requested keys as "feature_keys" for
+	// GetFeaturesHealth. It is a copy: Before hooks may modify it to change
+	// what is sent.
+	RequestContext RequestContext
+}
+
+// Hook adds behaviour around client operations. Before hooks run in
+// registration order, global hooks first; the other stages run in reverse
+// order. A Before hook returning an error aborts the operation with that
+// error; only the hooks whose Before ran, including the failing one, then see
+// Error and Finally. Otherwise After runs on success and Error on failure, and
+// Finally always runs.
+// The result passed to After is nil for operations other than evaluate.
+//
+// Embed BaseHook to implement only some stages.
+type Hook interface {
+	Before(ctx context.Context, hc *HookContext) error
+	After(ctx context.Context, hc *HookContext, res *EvalResult)
+	Error(ctx context.Context, hc *HookContext, err error)
+	Finally(ctx context.Context, hc *HookContext)
+}
+
+type BaseHook struct{}
+
+func (BaseHook) Before(context.Context, *HookContext) error       { return nil }
+func (BaseHook) After(context.Context, *HookContext, *EvalResult) {}
+func (BaseHook) Error(context.Context, *HookContext, error)       {}
+func (BaseHook) Finally(context.Context, *HookContext)            {}
+
+type hooksKey struct{}
+
+// ContextWithHooks returns a context that makes the client run hooks, after
+// the global ones, for operations called with it.
+func ContextWithHooks(ctx context.Context, hooks ...Hook) context.Context {
+	existing, _ := ctx.Value(hooksKey{}).([]Hook)
+
+	return context.WithValue(ctx, hooksKey{}, slices.Concat(existing, hooks))
+}
+
+func (c *Client) hooksFor(ctx context.Context) []Hook {
+	perCall, _ := ctx.Value(hooksKey{}).([]Hook)
+	if len(perCall) == 0 {
+		return c.cfg.Hooks
+	}
+
+	return slices.Concat(c.cfg.Hooks, perCall)
+}
+
+// withHooks runs fn between the hook stages. fn receives the request context
+// as modified by Before hooks. It reports whether fn was called.
+func (c *Client) withHooks(
+	ctx context.Context,
+	op Operation,
+	featureKey string,
+	req RequestContext,
+	fn func(RequestContext) (*EvalResult, error),
+) (bool, error) {
+	hooks := c.hooksFor(ctx)
+	if len(hooks) == 0 {
+		_, err := fn(req)
+
+		return true, err
+	}
+
+	hc := &HookContext{
+		Operation:      op,
+		FeatureKey:     featureKey,
+		RequestContext: maps.Clone(req),
+	}
+	if hc.RequestContext == nil {
+		hc.RequestContext = NewContext()
+	}
+
+	// started holds the hooks whose Before ran; only those are unwound.
+	var started []Hook
+
+	defer func() {
+		for _, h := range slices.Backward(started) {
+			h.Finally(ctx, hc)
+		}
+	}()
+
+	for i, h := range hooks {
+		started = hooks[:i+1]
+
+		if err := h.Before(ctx, hc); err != nil {
+			for _, h := range slices.Backward(started) {
+				h.Error(ctx, hc, err)
+			}
+
+			return false, err
+		}
+	}
+
+	res, err := fn(hc.RequestContext)
+
+	for _, h := range slices.Backward(hooks) {
+		if err != nil {
+			h.Error(ctx, hc, err)
+		} else {
+			h.After(ctx, hc, res)
+		}
+	}
+
+	return true, err
+}
@@ -0,0 +1,203 @@
+package togglr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingHook struct {
+	name   string
+	mu     *sync.Mutex
+	stages *[]string
+	before func(*HookContext) error
+}
+
+func (h recordingHook) record(stage string, hc *HookContext) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	*h.stages = append(*h.stages, fmt.Sprintf("%s:%s:%s", h.name, stage, hc.Operation))
+}
+
+func (h recordingHook) Before(_ context.Context, hc *HookContext) error {
+	h.record("before", hc)
+	if h.before != nil {
+		return h.before(hc)
+	}
+
+	return nil
+}
+
+func (h recordingHook) After(_ context.Context, hc *HookContext, _ *EvalResult) {
+	h.record("after", hc)
+}
+
+func (h recordingHook) Error(_ context.Context, hc *HookContext, _ error) {
+	h.record("error", hc)
+}
+
+func (h recordingHook) Finally(_ context.Context, hc *HookContext) {
+	h.record("finally", hc)
+}
+
+func TestClientHooksEvaluate(t *testing.T) {
+	var calls atomic.Int32
+	var seen atomic.Value
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", capturingEvaluateHandler(&calls, &seen, true, "on"))
+
+	var mu sync.Mutex
+	var stages []string
+
+	global := recordingHook{name: "global", mu: &mu, stages: &stages, before: func(hc *HookContext) error {
+		hc.RequestContext[AttrPlatform] = "ios"
+
+		return nil
+	}}
+	perCall := recordingHook{name: "call", mu: &mu, stages: &stages}
+
+	client := newTestClient(t, mux, WithHooks(global))
+
+	req := NewContext().WithUserID("u1")
+	res := client.EvaluateWithContext(ContextWithHooks(context.Background(), perCall), "new_ui", req)
+	require.NoError(t, res.Err())
+
+	assert.Equal(t, []string{
+		"global:before:evaluate",
+		"call:before:evaluate",
+		"call:after:evaluate",
+		"global:after:evaluate",
+		"call:finally:evaluate",
+		"global:finally:evaluate",
+	}, stages)
+	assert.Equal(t, "ios", seen.Load().(map[string]any)[AttrPlatform])
+	assert.NotContains(t, req, AttrPlatform)
+}
+
+func TestClientHooksBeforeAborts(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "on"))
+
+	var mu sync.Mutex
+	var stages []string
+	errDenied := errors.New("denied")
+
+	client := newTestClient(t, mux, WithHooks(recordingHook{name: "h", mu: &mu, stages: &stages,
+		before: func(*HookContext) error { return errDenied }}))
+
+	res := client.Evaluate("new_ui", NewContext())
+
+	assert.ErrorIs(t, res.Err(), errDenied)
+	assert.Equal(t, SourceError, res.Source())
+	assert.Zero(t, calls.Load())
+	assert.Equal(t, []string{"h:before:evaluate", "h:error:evaluate", "h:finally:evaluate"}, stages)
+}
+
+func TestClientHooksBeforeAbortsUnwindsStartedHooks(t *testing.T) {
+	var calls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "on"))
+
+	var mu sync.Mutex
+	var stages []string
+	errDenied := errors.New("denied")
+
+	client := newTestClient(t, mux, WithHooks(
+		recordingHook{name: "a", mu: &mu, stages: &stages},
+		recordingHook{name: "b", mu: &mu, stages: &stages, before: func(*HookContext) error { return errDenied }},
+		recordingHook{name: "c", mu: &mu, stages: &stages},
+	))
+
+	res := client.Evaluate("new_ui", NewContext())
+
+	assert.ErrorIs(t, res.Err(), errDenied)
+	assert.Equal(t, []string{
+		"a:before:evaluate", "b:before:evaluate",
+		"b:error:evaluate", "a:error:evaluate",
+		"b:finally:evaluate", "a:finally:evaluate",
+	}, stages)
+}
+
+func TestClientHooksFeaturesHealth(t *testing.T) {
+	var requested []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/health", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			FeatureKeys []string `json:"feature_keys"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		requested = req.FeatureKeys
+
+		features := make([]any, 0, len(req.FeatureKeys))
+		for _, key := range req.FeatureKeys {
+			features = append(features, healthBody(key, true, false, 0, 0.5))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"features": features})
+	})
+
+	var seen []string
+	hook := recordingHook{name: "h", mu: &sync.Mutex{}, stages: &[]string{}, before: func(hc *HookContext) error {
+		seen = hc.RequestContext["feature_keys"].([]string)
+		hc.RequestContext["feature_keys"] = append(seen, "extra")
+
+		return nil
+	}}
+
+	client := newTestClient(t, mux, WithHooks(hook))
+
+	keys := []string{"a", "b"}
+	_, err := client.GetFeaturesHealth(context.Background(), keys)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b"}, seen)
+	assert.Equal(t, []string{"a", "b", "extra"}, requested)
+	assert.Equal(t, []string{"a", "b"}, keys)
+}
+
+func TestClientHooksOtherOperations(t *testing.T) {
+	var calls atomic.Int32
+	rec := &recordingServer{}
+	mux := rec.handler(&calls, true, "on")
+	mux.HandleFunc("GET /sdk/v1/features/{feature_key}/health", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	var mu sync.Mutex
+	var stages []string
+
+	client := newTestClient(t, mux, WithRetries(0), WithHooks(recordingHook{name: "h", mu: &mu, stages: &stages,
+		before: func(hc *HookContext) error {
+			hc.RequestContext["service"] = "checkout"
+
+			return nil
+		}}))
+
+	ctx := context.Background()
+	require.NoError(t, client.TrackEvent(ctx, "new_ui", NewTrackEvent("A", EventTypeSuccess)))
+	require.NoError(t, client.ReportError(ctx, "new_ui", NewErrorReport("timeout", "slow")))
+	_, err := client.GetFeatureHealth(ctx, "new_ui")
+	require.Error(t, err)
+
+	assert.Equal(t, []string{
+		"h:before:track", "h:after:track", "h:finally:track",
+		"h:before:report_error", "h:after:report_error", "h:finally:report_error",
+		"h:before:health", "h:error:health", "h:finally:health",
+	}, stages)
+
+	assert.Equal(t, "checkout", rec.tracks[0]["context"].(map[string]any)["service"])
+	assert.Equal(t, "checkout", rec.reports[0]["context"].(map[string]any)["service"])
+}
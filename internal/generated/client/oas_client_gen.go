@@ -28,6 +28,12 @@ type Invoker interface {
 	//
 	// GET /sdk/v1/features/{feature_key}/health
 	GetFeatureHealth(ctx context.Context, params GetFeatureHealthParams) (GetFeatureHealthRes, error)
+	// GetFeaturesHealth invokes GetFeaturesHealth operation.
+	//
+	// Get health status of several features.
+	//
+	// POST /sdk/v1/features/health
+	GetFeaturesHealth(ctx context.Context, request *FeaturesHealthRequest) (GetFeaturesHealthRes, error)
 	// ReportFeatureError invokes ReportFeatureError operation.
 	//
 	// Report feature execution error (for auto-disable).
@@ -47,6 +53,15 @@ type Invoker interface {
 	//
 	// GET /sdk/v1/health
 	SdkV1HealthGet(ctx context.Context) (SdkV1HealthGetRes, error)
+	// StreamFlagChanges invokes StreamFlagChanges operation.
+	//
+	// Long-lived Server-Sent Events stream of flag changes for the project
+	// derived from the API key. Each event has type `flag_change`, an `id`
+	// that can be passed back in the `Last-Event-ID` header to resume, and
+	// a JSON encoded `FlagChangeEvent` as data.
+	//
+	// GET /sdk/v1/stream
+	StreamFlagChanges(ctx context.Context, params StreamFlagChangesParams) (StreamFlagChangesRes, error)
 	// TrackFeatureEvent invokes TrackFeatureEvent operation.
 	//
 	// Send a feedback event related to a feature evaluation. Events are written to TimescaleDB
@@ -186,6 +201,87 @@ func (c *Client) sendGetFeatureHealth(ctx context.Context, params GetFeatureHeal
 	return result, nil
 }
 
+// GetFeaturesHealth invokes GetFeaturesHealth operation.
+//
+// Get health status of several features.
+//
+// POST /sdk/v1/features/health
+func (c *Client) GetFeaturesHealth(ctx context.Context, request *FeaturesHealthRequest) (GetFeaturesHealthRes, error) {
+	res, err := c.sendGetFeaturesHealth(ctx, request)
+	return res, err
+}
+
+func (c *Client) sendGetFeaturesHealth(ctx context.Context, request *FeaturesHealthRequest) (res GetFeaturesHealthRes, err error) {
+	// Validate request before sending.
+	if err := func() error {
+		if err := request.Validate(); err != nil {
+			return err
+		}
+		return nil
+	}(); err != nil {
+		return res, errors.Wrap(err, "validate")
+	}
+
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/sdk/v1/features/health"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+	if err := encodeGetFeaturesHealthRequest(request, r); err != nil {
+		return res, errors.Wrap(err, "encode request")
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+
+			switch err := c.securityApiKeyAuth(ctx, GetFeaturesHealthOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"ApiKeyAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	result, err := decodeGetFeaturesHealthResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
 // ReportFeatureError invokes ReportFeatureError operation.
 //
 // Report feature execution error (for auto-disable).
@@ -405,6 +501,94 @@ func (c *Client) sendSdkV1HealthGet(ctx context.Context) (res SdkV1HealthGetRes,
 	return result, nil
 }
 
+// StreamFlagChanges invokes StreamFlagChanges operation.
+//
+// Long-lived Server-Sent Events stream of flag changes for the project
+// derived from the API key. Each event has type `flag_change`, an `id`
+// that can be passed back in the `Last-Event-ID` header to resume, and
+// a JSON encoded `FlagChangeEvent` as data.
+//
+// GET /sdk/v1/stream
+func (c *Client) StreamFlagChanges(ctx context.Context, params StreamFlagChangesParams) (StreamFlagChangesRes, error) {
+	res, err := c.sendStreamFlagChanges(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendStreamFlagChanges(ctx context.Context, params StreamFlagChangesParams) (res StreamFlagChangesRes, err error) {
+
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/sdk/v1/stream"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	h := uri.NewHeaderEncoder(r.Header)
+	{
+		cfg := uri.HeaderParameterEncodingConfig{
+			Name:    "Last-Event-ID",
+			Explode: false,
+		}
+		if err := h.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.LastEventID.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode header")
+		}
+	}
+
+	{
+		type bitset = [1]uint8
+		var satisfied bitset
+		{
+
+			switch err := c.securityApiKeyAuth(ctx, StreamFlagChangesOperation, r); {
+			case err == nil: // if NO error
+				satisfied[0] |= 1 << 0
+			case errors.Is(err, ogenerrors.ErrSkipClientSecurity):
+				// Skip this security.
+			default:
+				return res, errors.Wrap(err, "security \"ApiKeyAuth\"")
+			}
+		}
+
+		if ok := func() bool {
+		nextRequirement:
+			for _, requirement := range []bitset{
+				{0b00000001},
+			} {
+				for i, mask := range requirement {
+					if satisfied[i]&mask != mask {
+						continue nextRequirement
+					}
+				}
+				return true
+			}
+			return false
+		}(); !ok {
+			return res, ogenerrors.ErrSecurityRequirementIsNotSatisfied
+		}
+	}
+
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	result, err := decodeStreamFlagChangesResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
 // TrackFeatureEvent invokes TrackFeatureEvent operation.
 //
 // Send a feedback event related to a feature evaluation. Events are written to TimescaleDB
@@ -5,6 +5,10 @@ type GetFeatureHealthRes interface {
 	getFeatureHealthRes()
 }
 
+type GetFeaturesHealthRes interface {
+	getFeaturesHealthRes()
+}
+
 type ReportFeatureErrorRes interface {
 	reportFeatureErrorRes()
 }
@@ -17,6 +21,10 @@ type SdkV1HealthGetRes interface {
 	sdkV1HealthGetRes()
 }
 
+type StreamFlagChangesRes interface {
+	streamFlagChangesRes()
+}
+
 type TrackFeatureEventRes interface {
 	trackFeatureEventRes()
 }
@@ -7,8 +7,10 @@ type OperationName = string
 
 const (
 	GetFeatureHealthOperation                    OperationName = "GetFeatureHealth"
+	GetFeaturesHealthOperation                   OperationName = "GetFeaturesHealth"
 	ReportFeatureErrorOperation                  OperationName = "ReportFeatureError"
 	SdkV1FeaturesFeatureKeyEvaluatePostOperation OperationName = "SdkV1FeaturesFeatureKeyEvaluatePost"
 	SdkV1HealthGetOperation                      OperationName = "SdkV1HealthGet"
+	StreamFlagChangesOperation                   OperationName = "StreamFlagChanges"
 	TrackFeatureEventOperation                   OperationName = "TrackFeatureEvent"
 )
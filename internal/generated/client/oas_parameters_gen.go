@@ -17,6 +17,11 @@ type SdkV1FeaturesFeatureKeyEvaluatePostParams struct {
 	FeatureKey string
 }
 
+// StreamFlagChangesParams is parameters of StreamFlagChanges operation.
+type StreamFlagChangesParams struct {
+	LastEventID OptString
+}
+
 // TrackFeatureEventParams is parameters of TrackFeatureEvent operation.
 type TrackFeatureEventParams struct {
 	FeatureKey string
@@ -3,6 +3,7 @@
 package api
 
 import (
+	"io"
 	"time"
 
 	"github.com/go-faster/errors"
@@ -66,6 +67,7 @@ func (s *ErrorBadRequest) SetError(val ErrorBadRequestError) {
 }
 
 func (*ErrorBadRequest) getFeatureHealthRes()                    {}
+func (*ErrorBadRequest) getFeaturesHealthRes()                   {}
 func (*ErrorBadRequest) reportFeatureErrorRes()                  {}
 func (*ErrorBadRequest) sdkV1FeaturesFeatureKeyEvaluatePostRes() {}
 func (*ErrorBadRequest) trackFeatureEventRes()                   {}
@@ -115,8 +117,10 @@ func (s *ErrorInternalServerError) SetError(val ErrorInternalServerErrorError) {
 }
 
 func (*ErrorInternalServerError) getFeatureHealthRes()                    {}
+func (*ErrorInternalServerError) getFeaturesHealthRes()                   {}
 func (*ErrorInternalServerError) reportFeatureErrorRes()                  {}
 func (*ErrorInternalServerError) sdkV1FeaturesFeatureKeyEvaluatePostRes() {}
+func (*ErrorInternalServerError) streamFlagChangesRes()                   {}
 func (*ErrorInternalServerError) trackFeatureEventRes()                   {}
 
 type ErrorInternalServerErrorError struct {
@@ -195,8 +199,10 @@ func (s *ErrorStatusCode) SetResponse(val Error) {
 }
 
 func (*ErrorStatusCode) getFeatureHealthRes()                    {}
+func (*ErrorStatusCode) getFeaturesHealthRes()                   {}
 func (*ErrorStatusCode) reportFeatureErrorRes()                  {}
 func (*ErrorStatusCode) sdkV1FeaturesFeatureKeyEvaluatePostRes() {}
+func (*ErrorStatusCode) streamFlagChangesRes()                   {}
 func (*ErrorStatusCode) trackFeatureEventRes()                   {}
 
 // Merged schema.
@@ -248,8 +254,10 @@ func (s *ErrorUnauthorized) SetError(val ErrorUnauthorizedError) {
 }
 
 func (*ErrorUnauthorized) getFeatureHealthRes()                    {}
+func (*ErrorUnauthorized) getFeaturesHealthRes()                   {}
 func (*ErrorUnauthorized) reportFeatureErrorRes()                  {}
 func (*ErrorUnauthorized) sdkV1FeaturesFeatureKeyEvaluatePostRes() {}
+func (*ErrorUnauthorized) streamFlagChangesRes()                   {}
 func (*ErrorUnauthorized) trackFeatureEventRes()                   {}
 
 type ErrorUnauthorizedError struct {
@@ -283,6 +291,9 @@ type EvaluateResponse struct {
 	FeatureKey string `json:"feature_key"`
 	Enabled    bool   `json:"enabled"`
 	Value      string `json:"value"`
+	// Context attributes the feature's rules depend on. Clients may use
+	// this to build cache keys from the relevant attributes only.
+	Attributes []string `json:"attributes"`
 }
 
 // GetFeatureKey returns the value of FeatureKey.
@@ -300,6 +311,11 @@ func (s *EvaluateResponse) GetValue() string {
 	return s.Value
 }
 
+// GetAttributes returns the value of Attributes.
+func (s *EvaluateResponse) GetAttributes() []string {
+	return s.Attributes
+}
+
 // SetFeatureKey sets the value of FeatureKey.
 func (s *EvaluateResponse) SetFeatureKey(val string) {
 	s.FeatureKey = val
@@ -315,6 +331,11 @@ func (s *EvaluateResponse) SetValue(val string) {
 	s.Value = val
 }
 
+// SetAttributes sets the value of Attributes.
+func (s *EvaluateResponse) SetAttributes(val []string) {
+	s.Attributes = val
+}
+
 func (*EvaluateResponse) sdkV1FeaturesFeatureKeyEvaluatePostRes() {}
 
 // Ref: #/components/schemas/FeatureErrorReport
@@ -448,6 +469,65 @@ func (s *FeatureHealth) SetLastErrorAt(val OptDateTime) {
 
 func (*FeatureHealth) getFeatureHealthRes() {}
 
+// Ref: #/components/schemas/FeaturesHealthRequest
+type FeaturesHealthRequest struct {
+	FeatureKeys []string `json:"feature_keys"`
+}
+
+// GetFeatureKeys returns the value of FeatureKeys.
+func (s *FeaturesHealthRequest) GetFeatureKeys() []string {
+	return s.FeatureKeys
+}
+
+// SetFeatureKeys sets the value of FeatureKeys.
+func (s *FeaturesHealthRequest) SetFeatureKeys(val []string) {
+	s.FeatureKeys = val
+}
+
+// Ref: #/components/schemas/FeaturesHealthResponse
+type FeaturesHealthResponse struct {
+	Features []FeatureHealth `json:"features"`
+	// Requested feature keys that do not exist.
+	NotFound []string `json:"not_found"`
+}
+
+// GetFeatures returns the value of Features.
+func (s *FeaturesHealthResponse) GetFeatures() []FeatureHealth {
+	return s.Features
+}
+
+// GetNotFound returns the value of NotFound.
+func (s *FeaturesHealthResponse) GetNotFound() []string {
+	return s.NotFound
+}
+
+// SetFeatures sets the value of Features.
+func (s *FeaturesHealthResponse) SetFeatures(val []FeatureHealth) {
+	s.Features = val
+}
+
+// SetNotFound sets the value of NotFound.
+func (s *FeaturesHealthResponse) SetNotFound(val []string) {
+	s.NotFound = val
+}
+
+func (*FeaturesHealthResponse) getFeaturesHealthRes() {}
+
+// GetFeaturesHealthMethodNotAllowed is response for GetFeaturesHealth operation.
+type GetFeaturesHealthMethodNotAllowed struct{}
+
+func (*GetFeaturesHealthMethodNotAllowed) getFeaturesHealthRes() {}
+
+// GetFeaturesHealthNotFound is response for GetFeaturesHealth operation.
+type GetFeaturesHealthNotFound struct{}
+
+func (*GetFeaturesHealthNotFound) getFeaturesHealthRes() {}
+
+// GetFeaturesHealthNotImplemented is response for GetFeaturesHealth operation.
+type GetFeaturesHealthNotImplemented struct{}
+
+func (*GetFeaturesHealthNotImplemented) getFeaturesHealthRes() {}
+
 // Ref: #/components/schemas/HealthResponse
 type HealthResponse struct {
 	Status     HealthResponseStatus `json:"status"`
@@ -762,6 +842,22 @@ func (s *SdkV1HealthGetDef) SetStatusCode(val int) {
 
 func (*SdkV1HealthGetDef) sdkV1HealthGetRes() {}
 
+type StreamFlagChangesOK struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s StreamFlagChangesOK) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+func (*StreamFlagChangesOK) streamFlagChangesRes() {}
+
 // TrackFeatureEventAccepted is response for TrackFeatureEvent operation.
 type TrackFeatureEventAccepted struct{}
 
@@ -19,6 +19,42 @@ type Metrics interface {
 	ObserveTrackEventLatency(d time.Duration)
 }
 
+// ErrorReportMetrics is optionally implemented by a Metrics to count error
+// reports that client-side aggregation collapsed or dropped.
+type ErrorReportMetrics interface {
+	IncErrorReportDropped(reason string)
+	IncErrorReportCollapsed()
+}
+
+// FeatureHealthMetrics is optionally implemented by a Metrics to count
+// features found auto-disabled by health-aware evaluation.
+type FeatureHealthMetrics interface {
+	IncFeatureAutoDisabled(featureKey string)
+}
+
+var (
+	_ ErrorReportMetrics   = NoOpMetrics{}
+	_ FeatureHealthMetrics = NoOpMetrics{}
+)
+
+func incErrorReportDropped(m Metrics, reason string) {
+	if em, ok := m.(ErrorReportMetrics); ok {
+		em.IncErrorReportDropped(reason)
+	}
+}
+
+func incErrorReportCollapsed(m Metrics) {
+	if em, ok := m.(ErrorReportMetrics); ok {
+		em.IncErrorReportCollapsed()
+	}
+}
+
+func incFeatureAutoDisabled(m Metrics, featureKey string) {
+	if hm, ok := m.(FeatureHealthMetrics); ok {
+		hm.IncFeatureAutoDisabled(featureKey)
+	}
+}
+
 type NoOpMetrics struct{}
 
 func (NoOpMetrics) IncEvaluateRequest()                         {}
@@ -29,9 +65,12 @@ func (NoOpMetrics) IncCacheMiss()                               {}
 func (NoOpMetrics) IncErrorReportRequest()                      {}
 func (NoOpMetrics) IncErrorReportError(code string)             {}
 func (NoOpMetrics) ObserveErrorReportLatency(d time.Duration)   {}
+func (NoOpMetrics) IncErrorReportDropped(reason string)         {}
+func (NoOpMetrics) IncErrorReportCollapsed()                    {}
 func (NoOpMetrics) IncFeatureHealthRequest()                    {}
 func (NoOpMetrics) IncFeatureHealthError(code string)           {}
 func (NoOpMetrics) ObserveFeatureHealthLatency(d time.Duration) {}
 func (NoOpMetrics) IncTrackEventRequest()                       {}
 func (NoOpMetrics) IncTrackEventError(code string)              {}
 func (NoOpMetrics) ObserveTrackEventLatency(d time.Duration)    {}
+func (NoOpMetrics) IncFeatureAutoDisabled(featureKey string)    {}
@@ -86,3 +86,201 @@ func WithClientCertAndKey(certPath, keyPath string) Option {
 		cfg.ClientKey = keyPath
 	}
 }
+
+// WithSnapshot periodically persists the evaluation cache to path and loads it
+// back on client start. It has no effect unless the cache is enabled.
+func WithSnapshot(path string, interval time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.SnapshotPath = path
+		cfg.SnapshotInterval = interval
+	}
+}
+
+func WithSnapshotEncryption(key []byte) Option {
+	return func(cfg *Config) {
+		cfg.SnapshotKey = key
+	}
+}
+
+func WithSnapshotMaxAge(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.SnapshotMaxAge = d
+	}
+}
+
+// WithBackgroundRefresh re-evaluates the most frequently hit cache entries
+// shortly before they expire. It has no effect unless the cache is enabled.
+func WithBackgroundRefresh(r Refresh) Option {
+	return func(cfg *Config) {
+		cfg.Refresh = r
+	}
+}
+
+// WithCacheKeyAttributes limits cache keys to the given attributes.
+func WithCacheKeyAttributes(attrs ...string) Option {
+	return func(cfg *Config) {
+		cfg.CacheKey.Include = attrs
+	}
+}
+
+// WithCacheKeyExcludedAttributes removes the given attributes from cache keys.
+func WithCacheKeyExcludedAttributes(attrs ...string) Option {
+	return func(cfg *Config) {
+		cfg.CacheKey.Exclude = attrs
+	}
+}
+
+// WithFeatureCacheKeyAttributes sets the attributes used in cache keys for a
+// single feature, overriding the global and server-reported sets.
+func WithFeatureCacheKeyAttributes(featureKey string, attrs ...string) Option {
+	return func(cfg *Config) {
+		if cfg.CacheKey.PerFeature == nil {
+			cfg.CacheKey.PerFeature = make(map[string][]string)
+		}
+		cfg.CacheKey.PerFeature[featureKey] = attrs
+	}
+}
+
+// WithServerCacheKeyHints builds cache keys from the attributes the server
+// reports a feature's rules depend on.
+func WithServerCacheKeyHints() Option {
+	return func(cfg *Config) {
+		cfg.CacheKey.ServerHints = true
+	}
+}
+
+// WithStream keeps a Server-Sent Events connection to the server and
+// invalidates cached evaluations as soon as a flag changes.
+func WithStream() Option {
+	return func(cfg *Config) {
+		cfg.StreamEnabled = true
+	}
+}
+
+func WithStreamBackoff(b Backoff) Option {
+	return func(cfg *Config) {
+		cfg.StreamBackoff = b
+	}
+}
+
+// WithStreamIdleTimeout reconnects the stream when nothing, not even a
+// heartbeat comment, was received for d.
+func WithStreamIdleTimeout(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.StreamIdleTimeout = d
+	}
+}
+
+// WithWatchInterval sets how often OnChange and Watch re-evaluate a feature.
+// Each wait is extended by a random duration of up to jitter.
+func WithWatchInterval(interval, jitter time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.WatchInterval = interval
+		cfg.WatchJitter = jitter
+	}
+}
+
+// WithDefaults sets the per-feature results returned when evaluation fails.
+func WithDefaults(defaults map[string]DefaultValue) Option {
+	return func(cfg *Config) {
+		if cfg.Defaults == nil {
+			cfg.Defaults = make(map[string]DefaultValue, len(defaults))
+		}
+		for k, v := range defaults {
+			cfg.Defaults[k] = v
+		}
+	}
+}
+
+// WithHealthAwareEvaluation makes evaluations of auto-disabled features return
+// disabled without calling the server. Health states of evaluated features are
+// refreshed every interval.
+func WithHealthAwareEvaluation(interval time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.HealthAware = true
+		cfg.HealthRefreshInterval = interval
+	}
+}
+
+// WithErrorReporting enables client-side sampling, aggregation and rate
+// limiting of error reports.
+func WithErrorReporting(r ErrorReporting) Option {
+	return func(cfg *Config) {
+		cfg.ErrorReporting = r
+	}
+}
+
+// WithReadinessPolicy sets when HealthHandler reports the client as ready.
+func WithReadinessPolicy(policy ReadinessPolicy) Option {
+	return func(cfg *Config) {
+		cfg.Readiness = policy
+	}
+}
+
+// WithReadinessLiveCheck makes every readiness probe check the server with
+// HealthCheck instead of relying on the outcome of the last call.
+func WithReadinessLiveCheck() Option {
+	return func(cfg *Config) {
+		cfg.ReadinessLiveCheck = true
+	}
+}
+
+// WithDebugHistory keeps the last n evaluations and errors for DebugHandler.
+func WithDebugHistory(n int) Option {
+	return func(cfg *Config) {
+		cfg.DebugHistory = n
+	}
+}
+
+// WithHooks registers hooks that run around every client operation.
+func WithHooks(hooks ...Hook) Option {
+	return func(cfg *Config) {
+		cfg.Hooks = append(cfg.Hooks, hooks...)
+	}
+}
+
+// WithDefaultAttributes adds attributes sent with every evaluation, track
+// event and error report, for example StandardAttributes.
+func WithDefaultAttributes(attrs map[string]any) Option {
+	return func(cfg *Config) {
+		if cfg.DefaultAttributes == nil {
+			cfg.DefaultAttributes = make(map[string]any, len(attrs))
+		}
+		for k, v := range attrs {
+			cfg.DefaultAttributes[k] = v
+		}
+	}
+}
+
+// WithDefaultAttributesInCacheKey makes default attributes part of cache
+// keys. They are left out by default, so that snapshots can be shared by
+// instances with different host names.
+func WithDefaultAttributesInCacheKey() Option {
+	return func(cfg *Config) {
+		cfg.CacheKey.DefaultAttributes = true
+	}
+}
+
+// WithAttributeValidation normalizes request, event and report attributes
+// before they are sent. See AttributeValidation for the modes.
+func WithAttributeValidation(mode AttributeValidation) Option {
+	return func(cfg *Config) {
+		cfg.AttributeValidation = mode
+	}
+}
+
+// WithPIIPolicy applies policy to the attributes of every evaluation, track
+// event and error report. See PIIPolicy.
+func WithPIIPolicy(policy PIIPolicy) Option {
+	return func(cfg *Config) {
+		cfg.PII = &policy
+	}
+}
+
+// WithHealthWatchThreshold sets the fraction of a feature's auto-disable
+// threshold at which WatchHealth reports a rising error rate.
+func WithHealthWatchThreshold(fraction float64) Option {
+	return func(cfg *Config) {
+		cfg.HealthWatchThreshold = fraction
+	}
+}
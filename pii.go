@@ -0,0 +1,211 @@
+package togglr
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/netip"
+	"sync"
+)
+
+type PIIAction int
+
+const (
+	PIIPass PIIAction = iota
+	// PIIHash replaces the value with its hex-encoded HMAC-SHA256 keyed with
+	// PIIPolicy.Salt. Equal values hash equally, so targeting by exact value
+	// and percentage rollouts keep working.
+	PIIHash
+	// PIITruncate keeps the network part of IP addresses (IPv4Bits and
+	// IPv6Bits), with any port removed, or the first Length characters of
+	// other values. Zero bits or length leave the value as is, except that a
+	// value which is not an IP address is dropped by a rule with bits but no
+	// length.
+	PIITruncate
+	PIIDrop
+)
+
+type PIIRule struct {
+	Action   PIIAction
+	IPv4Bits int
+	IPv6Bits int
+	Length   int
+}
+
+func HashPII() PIIRule {
+	return PIIRule{Action: PIIHash}
+}
+
+// TruncateIP keeps the /24 network of IPv4 and the /48 network of IPv6
+// addresses.
+func TruncateIP() PIIRule {
+	return PIIRule{Action: PIITruncate, IPv4Bits: 24, IPv6Bits: 48}
+}
+
+func TruncatePII(length int) PIIRule {
+	return PIIRule{Action: PIITruncate, Length: length}
+}
+
+func DropPII() PIIRule {
+	return PIIRule{Action: PIIDrop}
+}
+
+// PIIPolicy transforms attributes before they leave the process. It applies
+// to evaluation, track event and error report attributes, including
+// Config.DefaultAttributes, and cache fingerprints are computed from the
+// transformed values.
+type PIIPolicy struct {
+	Salt  []byte
+	Rules map[string]PIIRule
+}
+
+// DefaultPIIPolicy hashes emails and user ids and truncates IP addresses.
+func DefaultPIIPolicy(salt []byte) PIIPolicy {
+	return PIIPolicy{
+		Salt: salt,
+		Rules: map[string]PIIRule{
+			AttrUserEmail: HashPII(),
+			AttrUserID:    HashPII(),
+			AttrIP:        TruncateIP(),
+		},
+	}
+}
+
+func (p *PIIPolicy) validate() error {
+	for attr, rule := range p.Rules {
+		if rule.Action == PIIHash && len(p.Salt) == 0 {
+			return fmt.Errorf("%w: PII policy hashes %q but has no salt", ErrInvalidConfig, attr)
+		}
+	}
+
+	return nil
+}
+
+type piiProtector struct {
+	policy PIIPolicy
+	macs   sync.Pool
+}
+
+func newPIIProtector(policy PIIPolicy) *piiProtector {
+	p := &piiProtector{policy: policy}
+	p.macs.New = func() any {
+		return hmac.New(sha256.New, policy.Salt)
+	}
+
+	return p
+}
+
+// apply returns attrs transformed by the policy. attrs is not modified and is
+// returned as is when no rule matches. A nil protector returns attrs.
+func (p *piiProtector) apply(attrs map[string]any) map[string]any {
+	if p == nil {
+		return attrs
+	}
+
+	var out map[string]any
+
+	for attr, rule := range p.policy.Rules {
+		v, ok := attrs[attr]
+		if !ok || rule.Action == PIIPass {
+			continue
+		}
+
+		if out == nil {
+			out = make(map[string]any, len(attrs))
+			for k, v := range attrs {
+				out[k] = v
+			}
+		}
+
+		switch rule.Action {
+		case PIIHash:
+			out[attr] = p.hash(v)
+		case PIITruncate:
+			if truncated, ok := truncatePII(v, rule); ok {
+				out[attr] = truncated
+			} else {
+				delete(out, attr)
+			}
+		case PIIDrop:
+			delete(out, attr)
+		}
+	}
+
+	if out == nil {
+		return attrs
+	}
+
+	return out
+}
+
+func (p *piiProtector) hash(v any) string {
+	mac := p.macs.Get().(hash.Hash)
+	defer p.macs.Put(mac)
+
+	mac.Reset()
+	if s, ok := v.(string); ok {
+		mac.Write([]byte(s))
+	} else {
+		raw, _ := json.Marshal(v)
+		mac.Write(raw)
+	}
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// truncatePII applies a PIITruncate rule to v. It reports false when v must
+// be dropped: values under an IP-only rule that are not IP addresses are never
+// sent as is.
+func truncatePII(v any, rule PIIRule) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		s = fmt.Sprint(v)
+	}
+
+	if rule.IPv4Bits > 0 || rule.IPv6Bits > 0 {
+		if addr, ok := parseIP(s); ok {
+			bits := rule.IPv6Bits
+			if addr.Is4() {
+				bits = rule.IPv4Bits
+			}
+
+			if bits <= 0 {
+				return addr.String(), true
+			}
+
+			prefix, err := addr.Prefix(bits)
+			if err != nil {
+				return nil, false
+			}
+
+			return prefix.Addr().String(), true
+		}
+
+		if rule.Length <= 0 {
+			return nil, false
+		}
+	}
+
+	if r := []rune(s); rule.Length > 0 && len(r) > rule.Length {
+		return string(r[:rule.Length]), true
+	}
+
+	return s, true
+}
+
+// parseIP parses an IP address, with or without a port as in
+// http.Request.RemoteAddr.
+func parseIP(s string) (netip.Addr, bool) {
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return addr.Unmap().WithZone(""), true
+	}
+
+	if addrPort, err := netip.ParseAddrPort(s); err == nil {
+		return addrPort.Addr().Unmap().WithZone(""), true
+	}
+
+	return netip.Addr{}, false
+}
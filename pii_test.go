@@ -0,0 +1,152 @@
+package togglr
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hmacHex(salt, value string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestPIIProtectorApply(t *testing.T) {
+	p := newPIIProtector(PIIPolicy{
+		Salt: []byte("salt"),
+		Rules: map[string]PIIRule{
+			AttrUserEmail:   HashPII(),
+			AttrIP:          TruncateIP(),
+			"ipv6":          TruncateIP(),
+			AttrCity:        TruncatePII(3),
+			"ssn":           DropPII(),
+			AttrCountryCode: {Action: PIIPass},
+		},
+	})
+
+	attrs := map[string]any{
+		AttrUserEmail:   "jane@example.com",
+		AttrIP:          "203.0.113.77",
+		"ipv6":          "2001:db8:abcd:12::1",
+		AttrCity:        "Zürich",
+		"ssn":           "078-05-1120",
+		AttrCountryCode: "DE",
+	}
+
+	out := p.apply(attrs)
+
+	assert.Equal(t, map[string]any{
+		AttrUserEmail:   hmacHex("salt", "jane@example.com"),
+		AttrIP:          "203.0.113.0",
+		"ipv6":          "2001:db8:abcd::",
+		AttrCity:        "Zür",
+		AttrCountryCode: "DE",
+	}, out)
+	assert.Equal(t, "jane@example.com", attrs[AttrUserEmail], "input must not be modified")
+
+	untouched := map[string]any{AttrCountryCode: "DE"}
+	assert.Equal(t, untouched, p.apply(untouched))
+
+	var nilProtector *piiProtector
+	assert.Equal(t, attrs, nilProtector.apply(attrs))
+}
+
+func TestPIITruncateIP(t *testing.T) {
+	p := newPIIProtector(PIIPolicy{Rules: map[string]PIIRule{AttrIP: TruncateIP()}})
+
+	tests := []struct {
+		in   any
+		want any
+	}{
+		{"203.0.113.77:54321", "203.0.113.0"},
+		{"[2001:db8:abcd:12::1]:443", "2001:db8:abcd::"},
+		{"::ffff:203.0.113.77", "203.0.113.0"},
+		{"fe80::1%eth0", "fe80::"},
+		{"not-an-ip", nil},
+		{"203.0.113.77:http", nil},
+		{42, nil},
+	}
+
+	for _, tt := range tests {
+		out := p.apply(map[string]any{AttrIP: tt.in, AttrCity: "Berlin"})
+
+		if tt.want == nil {
+			assert.NotContains(t, out, AttrIP, "%v must be dropped", tt.in)
+		} else {
+			assert.Equal(t, tt.want, out[AttrIP], "%v", tt.in)
+		}
+		assert.Equal(t, "Berlin", out[AttrCity])
+	}
+}
+
+func TestPIIHashNonString(t *testing.T) {
+	p := newPIIProtector(PIIPolicy{Salt: []byte("salt"), Rules: map[string]PIIRule{AttrUserID: HashPII()}})
+
+	assert.Equal(t, hmacHex("salt", "42"), p.apply(map[string]any{AttrUserID: 42})[AttrUserID])
+	assert.Equal(t, p.apply(map[string]any{AttrUserID: "u1"}), p.apply(map[string]any{AttrUserID: "u1"}))
+}
+
+func TestPIIPolicyRequiresSalt(t *testing.T) {
+	_, err := NewClientWithDefaults("key", WithPIIPolicy(DefaultPIIPolicy(nil)))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+}
+
+func TestClientPIIPolicy(t *testing.T) {
+	var calls atomic.Int32
+	var evaluated atomic.Value
+
+	rec := &recordingServer{}
+	mux := http.NewServeMux()
+	mux.Handle("/sdk/v1/features/{feature_key}/", rec.handler(&calls, true, "on"))
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", capturingEvaluateHandler(&calls, &evaluated, true, "on"))
+
+	client := newTestClient(t, mux,
+		WithCache(10, time.Minute),
+		WithDefaultAttributes(map[string]any{AttrIP: "10.1.2.3"}),
+		WithPIIPolicy(DefaultPIIPolicy([]byte("salt"))),
+	)
+
+	req := NewContext().WithUserID("u1").WithUserEmail("jane@example.com")
+	res := client.Evaluate("new_ui", req)
+	require.NoError(t, res.Err())
+
+	protected := map[string]any{
+		AttrUserID:    hmacHex("salt", "u1"),
+		AttrUserEmail: hmacHex("salt", "jane@example.com"),
+	}
+	assert.Equal(t, map[string]any{
+		AttrUserID:    protected[AttrUserID],
+		AttrUserEmail: protected[AttrUserEmail],
+		AttrIP:        "10.1.2.0",
+	}, evaluated.Load())
+
+	assert.Equal(t, client.fingerprint("new_ui", protected), client.Fingerprint("new_ui", req))
+	cached := client.Evaluate("new_ui", req)
+	assert.Equal(t, SourceCache, cached.Source())
+
+	ctx := context.Background()
+	event := NewTrackEvent("A", EventTypeSuccess).WithContext(AttrUserEmail, "jane@example.com")
+	require.NoError(t, client.TrackEvent(ctx, "new_ui", event))
+	require.NoError(t, client.ReportError(ctx, "new_ui", NewErrorReport("timeout", "slow").WithContext(AttrUserID, "u1")))
+
+	assert.Equal(t, map[string]any{
+		AttrUserEmail: protected[AttrUserEmail],
+		AttrIP:        "10.1.2.0",
+	}, rec.tracks[0]["context"])
+	assert.Equal(t, map[string]any{
+		AttrUserID: protected[AttrUserID],
+		AttrIP:     "10.1.2.0",
+	}, rec.reports[0]["context"])
+}
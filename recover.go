@@ -0,0 +1,124 @@
+package togglr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const errorTypePanic = "panic"
+
+type RecoverOption func(*recoverOptions)
+
+type recoverOptions struct {
+	repanic bool
+}
+
+// WithRepanic makes the recovery helpers panic again with the original value
+// once the panic has been reported.
+func WithRepanic() RecoverOption {
+	return func(o *recoverOptions) {
+		o.repanic = true
+	}
+}
+
+func newRecoverOptions(opts []RecoverOption) recoverOptions {
+	var o recoverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// RecoverAndReport recovers a panic and reports it for featureKey as an error
+// of type "panic", so that it counts towards the feature's auto-disable
+// threshold. It must be deferred directly:
+//
+//	defer client.RecoverAndReport(ctx, "new_checkout")
+//
+// The report is sent synchronously, bypassing client-side aggregation, so it
+// is not lost if the process is about to exit.
+func (c *Client) RecoverAndReport(ctx context.Context, featureKey string, opts ...RecoverOption) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	c.reportPanic(ctx, featureKey, v)
+
+	if newRecoverOptions(opts).repanic {
+		panic(v)
+	}
+}
+
+// Go runs fn in a new goroutine, recovering and reporting its panics for
+// featureKey.
+func (c *Client) Go(ctx context.Context, featureKey string, fn func(context.Context), opts ...RecoverOption) {
+	go func() {
+		defer c.RecoverAndReport(ctx, featureKey, opts...)
+
+		fn(ctx)
+	}()
+}
+
+// RecoverHandler wraps next, recovering and reporting its panics for
+// featureKey. Unless WithRepanic is given, the client gets a 500 response.
+// http.ErrAbortHandler is passed through without being reported.
+func (c *Client) RecoverHandler(featureKey string, next http.Handler, opts ...RecoverOption) http.Handler {
+	o := newRecoverOptions(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+
+			if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(v)
+			}
+
+			c.reportPanic(r.Context(), featureKey, v)
+
+			if o.repanic {
+				panic(v)
+			}
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *Client) reportPanic(ctx context.Context, featureKey string, v any) {
+	err, ok := v.(error)
+	if !ok {
+		err = fmt.Errorf("%v", v)
+	}
+
+	report := NewErrorReportFromError(err)
+	report.ErrorType = errorTypePanic
+	report.Context["panic_value"] = fmt.Sprintf("%v", v)
+	report.Context["panic_type"] = fmt.Sprintf("%T", v)
+
+	c.logger.Error("recovered panic", "feature_key", featureKey, "panic", v)
+
+	ctx = context.WithoutCancel(ctx)
+
+	_, err = c.withHooks(ctx, OperationReportError, featureKey, report.Context,
+		func(req RequestContext) (*EvalResult, error) {
+			attrs, err := c.prepareAttributes(OperationReportError, featureKey, req)
+			if err != nil {
+				return nil, err
+			}
+			report.Context = attrs
+
+			return nil, c.sendErrorReport(ctx, featureKey, report)
+		})
+	if err != nil {
+		c.logger.Warn("failed to report panic", "feature_key", featureKey, "error", err)
+	}
+}
@@ -0,0 +1,137 @@
+package togglr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRecoverAndReport(t *testing.T) {
+	var calls atomic.Int32
+	rec := &recordingServer{}
+	client := newTestClient(t, rec.handler(&calls, true, "on"),
+		WithErrorReporting(ErrorReporting{Window: time.Hour}))
+
+	panicky := func() {
+		defer client.RecoverAndReport(context.Background(), "feature")
+
+		panic("boom")
+	}
+
+	require.NotPanics(t, panicky)
+	require.NotPanics(t, panicky)
+
+	// Panics bypass aggregation: both are sent right away.
+	require.Len(t, rec.reports, 2)
+
+	report := rec.reports[0]
+	assert.Equal(t, "panic", report["error_type"])
+	assert.Equal(t, "boom", report["error_message"])
+
+	ctx := report["context"].(map[string]any)
+	assert.Equal(t, "boom", ctx["panic_value"])
+	assert.Equal(t, "string", ctx["panic_type"])
+
+	stack := ctx["stack"].([]any)
+	require.NotEmpty(t, stack)
+	assert.True(t, strings.HasPrefix(stack[0].(string), "github.com/togglr-project/togglr-sdk-go.TestClientRecoverAndReport.func"),
+		stack[0])
+}
+
+func TestClientRecoverAndReportValidatesAttributes(t *testing.T) {
+	long := strings.Repeat("x", MaxAttributeValueLength+10)
+
+	for _, mode := range []AttributeValidation{AttributeValidationLenient, AttributeValidationStrict} {
+		var calls atomic.Int32
+		rec := &recordingServer{}
+		client := newTestClient(t, rec.handler(&calls, true, "on"), WithAttributeValidation(mode))
+
+		require.NotPanics(t, func() {
+			defer client.RecoverAndReport(context.Background(), "feature")
+
+			panic(long)
+		})
+
+		if mode == AttributeValidationStrict {
+			assert.Empty(t, rec.reports, "invalid panic reports are not sent in strict mode")
+
+			continue
+		}
+
+		require.Len(t, rec.reports, 1)
+		ctx := rec.reports[0]["context"].(map[string]any)
+		assert.Len(t, ctx["panic_value"], MaxAttributeValueLength)
+	}
+}
+
+func TestClientRecoverAndReportRepanic(t *testing.T) {
+	var calls atomic.Int32
+	rec := &recordingServer{}
+	client := newTestClient(t, rec.handler(&calls, true, "on"))
+
+	err := errors.New("bad state")
+
+	assert.PanicsWithValue(t, err, func() {
+		defer client.RecoverAndReport(context.Background(), "feature", WithRepanic())
+
+		panic(err)
+	})
+
+	require.Len(t, rec.reports, 1)
+	assert.Equal(t, "bad state", rec.reports[0]["error_message"])
+}
+
+func TestClientGo(t *testing.T) {
+	var calls atomic.Int32
+	rec := &recordingServer{}
+	client := newTestClient(t, rec.handler(&calls, true, "on"))
+
+	done := make(chan struct{})
+	client.Go(context.Background(), "feature", func(context.Context) {
+		defer close(done)
+
+		panic("in goroutine")
+	})
+
+	<-done
+	require.Eventually(t, func() bool {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+
+		return len(rec.reports) == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestClientRecoverHandler(t *testing.T) {
+	var calls atomic.Int32
+	rec := &recordingServer{}
+	client := newTestClient(t, rec.handler(&calls, true, "on"))
+
+	h := client.RecoverHandler("feature", http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("handler failed")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Len(t, rec.reports, 1)
+	assert.Equal(t, "handler failed", rec.reports[0]["error_message"])
+
+	abort := client.RecoverHandler("feature", http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.Panics(t, func() {
+		abort.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.Len(t, rec.reports, 1)
+}
@@ -0,0 +1,266 @@
+package togglr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Refresh struct {
+	Interval    time.Duration // how often the refresher runs; zero disables it
+	Ahead       time.Duration // refresh entries expiring within this window
+	Concurrency int           // max evaluations in flight per cycle
+	Budget      int           // max evaluations per cycle
+}
+
+func DefaultRefresh() Refresh {
+	return Refresh{
+		Interval:    time.Second,
+		Ahead:       2 * time.Second,
+		Concurrency: 4,
+		Budget:      100,
+	}
+}
+
+type hotEntry struct {
+	featureKey string
+	req        RequestContext
+	hits       uint64
+}
+
+// hotTracker counts cache hits per cache key and remembers the request
+// context needed to re-evaluate the entry. Counts are halved every refresh
+// cycle so that the ranking follows recent traffic.
+type hotTracker struct {
+	mu       sync.Mutex
+	items    map[string]*hotEntry
+	capacity int
+}
+
+func newHotTracker(capacity int) *hotTracker {
+	return &hotTracker{
+		items:    make(map[string]*hotEntry),
+		capacity: capacity,
+	}
+}
+
+func (t *hotTracker) record(key, featureKey string, req RequestContext, hit bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if entry, ok := t.items[key]; ok {
+		if hit {
+			entry.hits++
+		}
+
+		return
+	}
+
+	// Pruned back to capacity on every cycle.
+	if len(t.items) >= 2*t.capacity {
+		return
+	}
+
+	entry := &hotEntry{featureKey: featureKey, req: maps.Clone(req)}
+	if hit {
+		entry.hits = 1
+	}
+	t.items[key] = entry
+}
+
+func (t *hotTracker) forFeature(featureKey string) []refreshCandidate {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var out []refreshCandidate
+	for key, entry := range t.items {
+		if entry.featureKey == featureKey {
+			out = append(out, refreshCandidate{key: key, featureKey: featureKey, req: entry.req, hits: entry.hits})
+		}
+	}
+
+	return out
+}
+
+type refreshCandidate struct {
+	key        string
+	featureKey string
+	req        RequestContext
+	hits       uint64
+}
+
+func (t *hotTracker) candidates(cache *LRUCache, ahead time.Duration, budget int) []refreshCandidate {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var all, due []refreshCandidate
+
+	for key, entry := range t.items {
+		cached, ok := cache.GetStale(key)
+		if !ok {
+			delete(t.items, key)
+
+			continue
+		}
+
+		c := refreshCandidate{key: key, featureKey: entry.featureKey, req: entry.req, hits: entry.hits}
+		all = append(all, c)
+		if entry.hits > 0 && cached.Expires.Sub(now) <= ahead {
+			due = append(due, c)
+		}
+
+		entry.hits /= 2
+	}
+
+	if len(all) > t.capacity {
+		sort.Slice(all, func(i, j int) bool { return all[i].hits > all[j].hits })
+		for _, c := range all[t.capacity:] {
+			delete(t.items, c.key)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].hits > due[j].hits })
+	if budget > 0 && len(due) > budget {
+		due = due[:budget]
+	}
+
+	return due
+}
+
+func (c *Client) runRefreshLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.Refresh.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.refreshCycle()
+		}
+	}
+}
+
+func (c *Client) refreshCycle() {
+	due := c.hot.candidates(c.cache, c.cfg.Refresh.Ahead, c.cfg.Refresh.Budget)
+	if len(due) == 0 {
+		return
+	}
+
+	ctx, cancel := c.doneContext()
+	defer cancel()
+
+	var failed int
+	var mu sync.Mutex
+
+	forEachLimited(len(due), c.cfg.Refresh.Concurrency, func(i int) {
+		if err := c.refreshEntry(ctx, due[i].featureKey, due[i].req); err != nil {
+			mu.Lock()
+			failed++
+			mu.Unlock()
+		}
+	})
+
+	c.logger.Debug("refresh cycle finished", "refreshed", len(due)-failed, "failed", failed)
+}
+
+// refreshEntry evaluates featureKey against the server and stores the result
+// in the cache, bypassing any cached value.
+func (c *Client) refreshEntry(ctx context.Context, featureKey string, req RequestContext) error {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	gen := c.gens.current(featureKey)
+
+	value, enabled, found, err := c.evaluateWithRetries(ctx, featureKey, req, c.cfg.Retries)
+	if err != nil {
+		return err
+	}
+
+	fp := c.fingerprint(featureKey, req)
+	c.gens.ifCurrent(featureKey, gen, func() {
+		c.cache.SetEntry(cacheKey(featureKey, fp), &CacheEntry{
+			FeatureKey:  featureKey,
+			Fingerprint: fp,
+			Value:       value,
+			Enabled:     enabled,
+			Found:       found,
+		})
+	})
+
+	return nil
+}
+
+// Prefetch evaluates every feature key for every context and stores the
+// results in the cache. With no contexts, an empty context is used.
+func (c *Client) Prefetch(ctx context.Context, keys []string, contexts []RequestContext) error {
+	if c.cache == nil {
+		return ErrCacheDisabled
+	}
+
+	if len(contexts) == 0 {
+		contexts = []RequestContext{NewContext()}
+	}
+
+	concurrency := c.cfg.Refresh.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultRefresh().Concurrency
+	}
+
+	errs := make([]error, len(keys)*len(contexts))
+
+	forEachLimited(len(errs), concurrency, func(i int) {
+		featureKey := keys[i/len(contexts)]
+
+		req, err := c.prepareAttributes(OperationEvaluate, featureKey, contexts[i%len(contexts)])
+		if err != nil {
+			errs[i] = fmt.Errorf("prefetch %s: %w", featureKey, err)
+
+			return
+		}
+
+		if err := c.refreshEntry(ctx, featureKey, req); err != nil {
+			errs[i] = fmt.Errorf("prefetch %s: %w", featureKey, err)
+
+			return
+		}
+
+		if c.hot != nil {
+			c.hot.record(cacheKey(featureKey, c.fingerprint(featureKey, req)), featureKey, req, false)
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+func forEachLimited(n, limit int, fn func(i int)) {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,123 @@
+package togglr
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotTrackerCandidates(t *testing.T) {
+	cache := NewLRUCache(10, time.Millisecond)
+	tracker := newHotTracker(10)
+
+	for _, key := range []string{"a", "b", "c"} {
+		cache.Set(key, "v", true, true)
+	}
+	cache.SetEntry("fresh", &CacheEntry{Expires: time.Now().Add(time.Hour)})
+
+	for i := 0; i < 3; i++ {
+		tracker.record("a", "fa", nil, true)
+	}
+	tracker.record("b", "fb", nil, true)
+	tracker.record("c", "fc", nil, false)
+	tracker.record("fresh", "ff", nil, true)
+	tracker.record("gone", "fg", nil, true)
+
+	due := tracker.candidates(cache, time.Second, 1)
+	require.Len(t, due, 1)
+	assert.Equal(t, "a", due[0].key)
+
+	due = tracker.candidates(cache, time.Second, 0)
+	require.Len(t, due, 1)
+	assert.Equal(t, "a", due[0].key, "hit counts decay between cycles")
+
+	assert.NotContains(t, tracker.items, "gone")
+}
+
+func TestClientPrefetch(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "on"))
+
+	client := newTestClient(t, mux, WithCache(10, time.Minute))
+
+	contexts := []RequestContext{
+		NewContext().WithUserID("u1"),
+		NewContext().WithUserID("u2"),
+	}
+	require.NoError(t, client.Prefetch(context.Background(), []string{"f1", "f2"}, contexts))
+	assert.Equal(t, int32(4), calls.Load())
+
+	res := client.Evaluate("f2", NewContext().WithUserID("u1"))
+	require.NoError(t, res.Err())
+	assert.Equal(t, "on", res.Value())
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestClientPrefetchWithoutCache(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+
+	err := client.Prefetch(context.Background(), []string{"f1"}, nil)
+	assert.ErrorIs(t, err, ErrCacheDisabled)
+}
+
+func TestClientBackgroundRefresh(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "on"))
+
+	metrics := &testMetrics{}
+	client := newTestClient(t, mux,
+		WithMetrics(metrics),
+		WithCache(10, 50*time.Millisecond),
+		WithBackgroundRefresh(Refresh{
+			Interval:    10 * time.Millisecond,
+			Ahead:       30 * time.Millisecond,
+			Concurrency: 2,
+			Budget:      10,
+		}),
+	)
+
+	req := NewContext().WithUserID("u1")
+	deadline := time.Now().Add(300 * time.Millisecond)
+
+	for time.Now().Before(deadline) {
+		res := client.Evaluate("hot", req)
+		require.NoError(t, res.Err())
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	assert.Equal(t, int32(1), metrics.cacheMisses.Load(), "only the first evaluation should miss")
+	assert.Greater(t, calls.Load(), int32(1), "entry should have been refreshed in the background")
+}
+
+func TestClientHotTrackerCountsOnlyCacheHits(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "on"))
+
+	client := newTestClient(t, mux,
+		WithCache(10, time.Minute),
+		WithBackgroundRefresh(Refresh{Interval: time.Hour}),
+	)
+
+	req := NewContext().WithUserID("u1")
+	hits := func() uint64 {
+		candidates := client.hot.forFeature("f")
+		require.Len(t, candidates, 1)
+
+		return candidates[0].hits
+	}
+
+	client.Evaluate("f", req)
+	assert.Zero(t, hits(), "a cache miss is not a hit")
+
+	client.Evaluate("f", req)
+	client.Evaluate("f", req)
+	assert.Equal(t, uint64(2), hits())
+}
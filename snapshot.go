@@ -0,0 +1,316 @@
+package togglr
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Snapshot file layout:
+//
+//	magic[4] | version u8 | flags u8 | payload length u32 | payload | sha256[32]
+//
+// The checksum covers everything before it. When the encrypted flag is set,
+// the payload is nonce || AES-256-GCM ciphertext of the JSON document.
+const (
+	snapshotVersion       = 1
+	snapshotFlagEncrypted = 1 << 0
+	snapshotHeaderSize    = 10
+)
+
+var snapshotMagic = [4]byte{'T', 'G', 'L', 'S'}
+
+var (
+	ErrSnapshotCorrupted    = errors.New("snapshot corrupted")
+	ErrSnapshotVersion      = errors.New("unsupported snapshot version")
+	ErrSnapshotKeyRequired  = errors.New("snapshot is encrypted but no key configured")
+	ErrSnapshotNotEncrypted = errors.New("snapshot is not encrypted but a key is configured")
+)
+
+type snapshotFile struct {
+	CreatedAt time.Time       `json:"created_at"`
+	Entries   []snapshotEntry `json:"entries"`
+}
+
+type snapshotEntry struct {
+	FeatureKey  string    `json:"feature_key"`
+	Fingerprint string    `json:"fingerprint"`
+	Value       string    `json:"value"`
+	Enabled     bool      `json:"enabled"`
+	Found       bool      `json:"found"`
+	StoredAt    time.Time `json:"stored_at"`
+}
+
+func encodeSnapshot(snap *snapshotFile, key []byte) ([]byte, error) {
+	payload, err := json.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("marshal snapshot: %w", err)
+	}
+
+	var flags byte
+	if len(key) > 0 {
+		payload, err = sealSnapshot(payload, key)
+		if err != nil {
+			return nil, err
+		}
+		flags |= snapshotFlagEncrypted
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, snapshotHeaderSize+len(payload)+sha256.Size))
+	buf.Write(snapshotMagic[:])
+	buf.WriteByte(snapshotVersion)
+	buf.WriteByte(flags)
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(payload)))
+	buf.Write(payload)
+
+	sum := sha256.Sum256(buf.Bytes())
+	buf.Write(sum[:])
+
+	return buf.Bytes(), nil
+}
+
+func decodeSnapshot(data, key []byte) (*snapshotFile, error) {
+	if len(data) < snapshotHeaderSize+sha256.Size {
+		return nil, ErrSnapshotCorrupted
+	}
+
+	if !bytes.Equal(data[:4], snapshotMagic[:]) {
+		return nil, ErrSnapshotCorrupted
+	}
+
+	body := data[:len(data)-sha256.Size]
+	sum := sha256.Sum256(body)
+	if !bytes.Equal(sum[:], data[len(data)-sha256.Size:]) {
+		return nil, ErrSnapshotCorrupted
+	}
+
+	if data[4] != snapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrSnapshotVersion, data[4])
+	}
+
+	flags := data[5]
+	size := binary.BigEndian.Uint32(data[6:snapshotHeaderSize])
+	payload := body[snapshotHeaderSize:]
+	if uint32(len(payload)) != size {
+		return nil, ErrSnapshotCorrupted
+	}
+
+	// The checksum is not keyed: with a key configured, only the GCM tag
+	// proves that the snapshot was written by the application.
+	if flags&snapshotFlagEncrypted == 0 && len(key) > 0 {
+		return nil, ErrSnapshotNotEncrypted
+	}
+
+	if flags&snapshotFlagEncrypted != 0 {
+		if len(key) == 0 {
+			return nil, ErrSnapshotKeyRequired
+		}
+
+		var err error
+		payload, err = openSnapshot(payload, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var snap snapshotFile
+	if err := json.Unmarshal(payload, &snap); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSnapshotCorrupted, err)
+	}
+
+	return &snap, nil
+}
+
+func snapshotAEAD(key []byte) (cipher.AEAD, error) {
+	derived := sha256.Sum256(key)
+
+	block, err := aes.NewCipher(derived[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func sealSnapshot(plain, key []byte) ([]byte, error) {
+	aead, err := snapshotAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+func openSnapshot(sealed, key []byte) ([]byte, error) {
+	aead, err := snapshotAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrSnapshotCorrupted
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plain, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decrypt: %w", ErrSnapshotCorrupted, err)
+	}
+
+	return plain, nil
+}
+
+func writeSnapshotFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// SaveSnapshot writes the current evaluation cache to the configured snapshot file.
+func (c *Client) SaveSnapshot() error {
+	if c.cache == nil || c.cfg.SnapshotPath == "" {
+		return nil
+	}
+
+	entries := c.cache.Entries()
+	snap := &snapshotFile{
+		CreatedAt: time.Now().UTC(),
+		Entries:   make([]snapshotEntry, 0, len(entries)),
+	}
+
+	for _, e := range entries {
+		if e.FeatureKey == "" {
+			continue
+		}
+
+		snap.Entries = append(snap.Entries, snapshotEntry{
+			FeatureKey:  e.FeatureKey,
+			Fingerprint: e.Fingerprint,
+			Value:       e.Value,
+			Enabled:     e.Enabled,
+			Found:       e.Found,
+			StoredAt:    e.StoredAt.UTC(),
+		})
+	}
+
+	data, err := encodeSnapshot(snap, c.cfg.SnapshotKey)
+	if err != nil {
+		return err
+	}
+
+	if err := writeSnapshotFile(c.cfg.SnapshotPath, data); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+
+	c.logger.Debug("snapshot saved", "path", c.cfg.SnapshotPath, "entries", len(snap.Entries))
+
+	return nil
+}
+
+// loadSnapshot fills the cache with stale entries from the snapshot file.
+// Entries expire at a random point within one cache TTL so that a fleet of
+// freshly started clients does not refresh everything at once.
+func (c *Client) loadSnapshot() error {
+	data, err := os.ReadFile(c.cfg.SnapshotPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+
+	snap, err := decodeSnapshot(data, c.cfg.SnapshotKey)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	loaded := 0
+
+	for _, e := range snap.Entries {
+		if c.cfg.SnapshotMaxAge > 0 && now.Sub(e.StoredAt) > c.cfg.SnapshotMaxAge {
+			continue
+		}
+
+		var jitter time.Duration
+		if c.cfg.CacheTTL > 0 {
+			jitter = mathrand.N(c.cfg.CacheTTL)
+		}
+
+		c.cache.SetEntry(cacheKey(e.FeatureKey, e.Fingerprint), &CacheEntry{
+			FeatureKey:  e.FeatureKey,
+			Fingerprint: e.Fingerprint,
+			Value:       e.Value,
+			Enabled:     e.Enabled,
+			Found:       e.Found,
+			StoredAt:    e.StoredAt,
+			Expires:     now.Add(jitter),
+			Stale:       true,
+		})
+		loaded++
+	}
+
+	c.logger.Info("snapshot loaded", "path", c.cfg.SnapshotPath, "entries", loaded)
+
+	if loaded > 0 {
+		c.stats.warm.Store(true)
+	}
+
+	return nil
+}
+
+func (c *Client) runSnapshotLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.SnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.SaveSnapshot(); err != nil {
+				c.logger.Warn("failed to save snapshot", "path", c.cfg.SnapshotPath, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package togglr
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSnapshot() *snapshotFile {
+	return &snapshotFile{
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Entries: []snapshotEntry{
+			{
+				FeatureKey:  "new_ui",
+				Fingerprint: "abc",
+				Value:       "B",
+				Enabled:     true,
+				Found:       true,
+				StoredAt:    time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	for _, key := range [][]byte{nil, []byte("secret")} {
+		data, err := encodeSnapshot(testSnapshot(), key)
+		require.NoError(t, err)
+
+		snap, err := decodeSnapshot(data, key)
+		require.NoError(t, err)
+		assert.Equal(t, testSnapshot(), snap)
+	}
+}
+
+func TestSnapshotEncryptedPayloadIsOpaque(t *testing.T) {
+	data, err := encodeSnapshot(testSnapshot(), []byte("secret"))
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(data), "new_ui")
+
+	_, err = decodeSnapshot(data, nil)
+	assert.ErrorIs(t, err, ErrSnapshotKeyRequired)
+
+	_, err = decodeSnapshot(data, []byte("other"))
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+}
+
+func TestSnapshotPlaintextRejectedWithKey(t *testing.T) {
+	data, err := encodeSnapshot(testSnapshot(), nil)
+	require.NoError(t, err)
+
+	_, err = decodeSnapshot(data, []byte("secret"))
+	assert.ErrorIs(t, err, ErrSnapshotNotEncrypted)
+}
+
+func TestSnapshotDetectsCorruption(t *testing.T) {
+	data, err := encodeSnapshot(testSnapshot(), nil)
+	require.NoError(t, err)
+
+	tampered := append([]byte(nil), data...)
+	tampered[snapshotHeaderSize+3] ^= 0xff
+	_, err = decodeSnapshot(tampered, nil)
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+
+	_, err = decodeSnapshot(data[:len(data)-1], nil)
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+
+	_, err = decodeSnapshot([]byte("garbage"), nil)
+	assert.ErrorIs(t, err, ErrSnapshotCorrupted)
+}
+
+func TestClientSnapshotPersistAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "togglr.snapshot")
+
+	var calls atomic.Int32
+	up := http.NewServeMux()
+	up.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "B"))
+
+	client := newTestClient(t, up,
+		WithCache(10, time.Minute),
+		WithSnapshot(path, 0),
+		WithSnapshotEncryption([]byte("secret")),
+	)
+
+	req := NewContext().WithUserID("u1")
+	res := client.Evaluate("new_ui", req)
+	require.NoError(t, res.Err())
+	require.NoError(t, client.Close())
+
+	_, err := os.Stat(path)
+	require.NoError(t, err)
+
+	down := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	restarted := newTestClient(t, down,
+		WithRetries(0),
+		WithCache(10, time.Nanosecond),
+		WithSnapshot(path, 0),
+		WithSnapshotEncryption([]byte("secret")),
+	)
+	assert.Equal(t, 1, restarted.cache.Size())
+
+	time.Sleep(time.Millisecond)
+
+	res = restarted.Evaluate("new_ui", req)
+	assert.Error(t, res.Err(), "the cause is kept on stale results")
+	assert.True(t, res.Enabled())
+	assert.Equal(t, "B", res.Value())
+	assert.Equal(t, SourceStale, res.Source())
+
+	value, err := res.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "B", value)
+
+	enabled, err := restarted.IsEnabled("new_ui", req)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClientSnapshotEntriesReportStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "togglr.snapshot")
+
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&calls, true, "B"))
+
+	client := newTestClient(t, mux, WithCache(10, time.Hour), WithSnapshot(path, 0))
+
+	req := NewContext().WithUserID("u1")
+	res := client.Evaluate("new_ui", req)
+	require.NoError(t, res.Err())
+	require.NoError(t, client.Close())
+
+	restarted := newTestClient(t, mux, WithCache(10, time.Hour), WithSnapshot(path, 0))
+
+	res = restarted.Evaluate("new_ui", req)
+	require.NoError(t, res.Err())
+	assert.Equal(t, "B", res.Value())
+	assert.Equal(t, SourceStale, res.Source(), "snapshot data must not read as fresh cache data")
+	assert.Equal(t, int32(1), calls.Load())
+}
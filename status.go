@@ -0,0 +1,205 @@
+package togglr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// ReadinessPolicy decides whether the client is ready to serve evaluations.
+type ReadinessPolicy func(Status) bool
+
+// ReadyWhenReachable is ready while the server is reachable: the last call to
+// it succeeded, or the health check passes when Config.ReadinessLiveCheck is
+// set. A Togglr outage then takes every replica out of rotation, so it only
+// suits services that cannot work without fresh flags.
+func ReadyWhenReachable(s Status) bool {
+	return s.Reachable
+}
+
+// ReadyWhenWarm is ready once the first successful evaluation or snapshot load
+// has happened, so that evaluations can be served without the server. It is
+// the default policy.
+func ReadyWhenWarm(s Status) bool {
+	return s.Warm
+}
+
+type Status struct {
+	Ready          bool           `json:"ready"`
+	Reachable      bool           `json:"reachable"`
+	ReachableError string         `json:"reachable_error,omitempty"`
+	LastSuccessAt  *time.Time     `json:"last_success_at,omitempty"`
+	LastFailureAt  *time.Time     `json:"last_failure_at,omitempty"`
+	Warm           bool           `json:"warm"`
+	Cache          *CacheStatus   `json:"cache,omitempty"`
+	Queues         map[string]int `json:"queues"`
+	Stream         *StreamStatus  `json:"stream,omitempty"`
+	AutoDisabled   []string       `json:"auto_disabled,omitempty"`
+}
+
+type CacheStatus struct {
+	Size     int     `json:"size"`
+	Capacity int     `json:"capacity"`
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRate  float64 `json:"hit_rate"`
+}
+
+type StreamStatus struct {
+	Connected bool `json:"connected"`
+}
+
+type clientStats struct {
+	lastSuccess atomic.Int64
+	lastFailure atomic.Int64
+	lastError   atomic.Value // string
+	warm        atomic.Bool
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
+}
+
+func (s *clientStats) markSuccess() {
+	s.lastSuccess.Store(time.Now().UnixNano())
+}
+
+// markFailure records a failed call to the server. Calls canceled by the
+// caller say nothing about the server and are ignored.
+func (s *clientStats) markFailure(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	s.lastError.Store(err.Error())
+	s.lastFailure.Store(time.Now().UnixNano())
+}
+
+// Status reports the state of the client. Reachability is derived from the
+// outcome of the last call to the server, so Status makes no request unless
+// Config.ReadinessLiveCheck is set, in which case it runs HealthCheck.
+func (c *Client) Status(ctx context.Context) Status {
+	status := Status{
+		Warm:   c.stats.warm.Load(),
+		Queues: map[string]int{"error_reports": 0},
+	}
+
+	if c.cfg.ReadinessLiveCheck {
+		ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+
+		_ = c.HealthCheck(ctx)
+	}
+
+	success, failure := c.stats.lastSuccess.Load(), c.stats.lastFailure.Load()
+	if success != 0 {
+		t := time.Unix(0, success)
+		status.LastSuccessAt = &t
+	}
+	if failure != 0 {
+		t := time.Unix(0, failure)
+		status.LastFailureAt = &t
+	}
+
+	status.Reachable = success != 0 && success >= failure
+	if !status.Reachable && failure != 0 {
+		status.ReachableError, _ = c.stats.lastError.Load().(string)
+	}
+
+	if c.cache != nil {
+		hits, misses := c.stats.cacheHits.Load(), c.stats.cacheMisses.Load()
+		status.Cache = &CacheStatus{
+			Size:     c.cache.Size(),
+			Capacity: c.cfg.CacheSize,
+			Hits:     hits,
+			Misses:   misses,
+		}
+		if hits+misses > 0 {
+			status.Cache.HitRate = float64(hits) / float64(hits+misses)
+		}
+	}
+
+	if c.errAgg != nil {
+		status.Queues["error_reports"] = c.errAgg.queued()
+	}
+
+	if c.cfg.StreamEnabled {
+		c.stream.mu.Lock()
+		status.Stream = &StreamStatus{Connected: c.stream.connected}
+		c.stream.mu.Unlock()
+	}
+
+	if c.guard != nil {
+		status.AutoDisabled = c.guard.disabledKeys()
+	}
+
+	policy := c.cfg.Readiness
+	if policy == nil {
+		policy = ReadyWhenWarm
+	}
+	status.Ready = policy(status)
+
+	return status
+}
+
+// HealthHandler returns an http.Handler for readiness probes that responds
+// with the client Status as JSON: 200 when it is ready according to
+// Config.Readiness, 503 otherwise.
+func (c *Client) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := c.Status(r.Context())
+
+		code := http.StatusOK
+		if !status.Ready {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(status)
+	})
+}
+
+// LivenessHandler returns an http.Handler for liveness probes. It always
+// responds with 200 and never contacts the server: an unreachable flag
+// backend is no reason to restart the process.
+func (c *Client) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"alive":true}` + "\n"))
+	})
+}
+
+func (g *healthGuard) disabledKeys() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var keys []string
+	for k, state := range g.states {
+		if state.disabled {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// queued returns the number of collapsed reports waiting for the end of the
+// window.
+func (a *errorAggregator) queued() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	n := 0
+	for _, p := range a.pending {
+		n += p.count
+	}
+
+	return n
+}
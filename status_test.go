@@ -0,0 +1,129 @@
+package togglr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveStatus(t *testing.T, client *Client) (int, map[string]any) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	client.HealthHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+
+	return w.Code, body
+}
+
+func healthEndpoint(calls *atomic.Int32, reachable *atomic.Bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+
+		if !reachable.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"status":      "ok",
+			"server_time": time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+func TestClientHealthHandlerLiveCheck(t *testing.T) {
+	var evaluations, healthChecks atomic.Int32
+	var reachable atomic.Bool
+	reachable.Store(true)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&evaluations, true, "on"))
+	mux.HandleFunc("GET /sdk/v1/health", healthEndpoint(&healthChecks, &reachable))
+
+	client := newTestClient(t, mux,
+		WithCache(10, time.Minute),
+		WithRetries(0),
+		WithReadinessPolicy(ReadyWhenReachable),
+		WithReadinessLiveCheck(),
+	)
+
+	code, body := serveStatus(t, client)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, body["reachable"])
+	assert.Equal(t, false, body["warm"])
+	assert.NotNil(t, body["last_success_at"])
+
+	req := NewContext().WithUserID("u1")
+	client.Evaluate("new_ui", req)
+	client.Evaluate("new_ui", req)
+	client.Evaluate("new_ui", req)
+
+	reachable.Store(false)
+
+	code, body = serveStatus(t, client)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, false, body["ready"])
+	assert.NotEmpty(t, body["reachable_error"])
+	assert.Equal(t, int32(2), healthChecks.Load())
+
+	cache := body["cache"].(map[string]any)
+	assert.Equal(t, float64(1), cache["size"])
+	assert.Equal(t, float64(2), cache["hits"])
+	assert.Equal(t, float64(1), cache["misses"])
+	assert.InDelta(t, 2.0/3, cache["hit_rate"], 1e-9)
+}
+
+func TestClientHealthHandlerDefault(t *testing.T) {
+	var evaluations, healthChecks atomic.Int32
+	var reachable atomic.Bool
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&evaluations, true, "on"))
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/track", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("GET /sdk/v1/health", healthEndpoint(&healthChecks, &reachable))
+
+	client := newTestClient(t, mux, WithRetries(0))
+
+	code, body := serveStatus(t, client)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, false, body["reachable"])
+
+	client.Evaluate("new_ui", NewContext())
+
+	code, body = serveStatus(t, client)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, body["warm"])
+	assert.Equal(t, true, body["reachable"])
+
+	require.Error(t, client.TrackEvent(context.Background(), "new_ui", NewTrackEvent("A", EventTypeSuccess)))
+
+	code, body = serveStatus(t, client)
+	assert.Equal(t, http.StatusOK, code, "a warm client stays ready while the server fails")
+	assert.Equal(t, false, body["reachable"])
+	assert.NotEmpty(t, body["reachable_error"])
+	assert.NotNil(t, body["last_failure_at"])
+	assert.Zero(t, healthChecks.Load(), "probes must not contact the server")
+}
+
+func TestClientLivenessHandler(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+
+	w := httptest.NewRecorder()
+	client.LivenessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"alive":true}`, w.Body.String())
+}
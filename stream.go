@@ -0,0 +1,377 @@
+package togglr
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The stream is described in specs/sdk.yml, but the generated
+// StreamFlagChanges reads the whole response body, which cannot work for a
+// long-lived event stream, so it is read by hand here.
+const (
+	streamPath            = "/sdk/v1/stream"
+	streamEventFlagChange = "flag_change"
+	streamMaxLineSize     = 1 << 20
+)
+
+var errStreamIdle = errors.New("no data received within the stream idle timeout")
+
+type FlagChangeKind string
+
+const (
+	FlagChangeUpdated FlagChangeKind = "updated"
+	FlagChangeDeleted FlagChangeKind = "deleted"
+)
+
+type FlagChange struct {
+	EventID        string         `json:"-"`
+	FeatureKey     string         `json:"feature_key"`
+	EnvironmentKey string         `json:"environment_key,omitempty"`
+	Kind           FlagChangeKind `json:"kind"`
+	Enabled        *bool          `json:"enabled,omitempty"`
+	Value          *string        `json:"value,omitempty"`
+	ChangedAt      time.Time      `json:"changed_at"`
+}
+
+type subscribers[T any] struct {
+	mu     sync.RWMutex
+	nextID uint64
+	fns    map[uint64]func(T)
+}
+
+func (s *subscribers[T]) add(fn func(T)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fns == nil {
+		s.fns = make(map[uint64]func(T))
+	}
+
+	id := s.nextID
+	s.nextID++
+	s.fns[id] = fn
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.fns, id)
+			s.mu.Unlock()
+		})
+	}
+}
+
+func (s *subscribers[T]) notify(v T) {
+	s.mu.RLock()
+	fns := make([]func(T), 0, len(s.fns))
+	for _, fn := range s.fns {
+		fns = append(fns, fn)
+	}
+	s.mu.RUnlock()
+
+	for _, fn := range fns {
+		fn(v)
+	}
+}
+
+// Subscribe registers fn to be called for every flag change received over the
+// change stream. The stream must be enabled with WithStream.
+func (c *Client) Subscribe(fn func(FlagChange)) (unsubscribe func()) {
+	return c.flagChanges.add(fn)
+}
+
+// featureGenerations counts the changes received for every feature. An
+// evaluation captures the generation before its request and only caches the
+// response if no change arrived meanwhile, so that a value read before a
+// change cannot be written back after the change invalidated the cache.
+type featureGenerations struct {
+	mu   sync.RWMutex
+	gens map[string]uint64
+}
+
+func (g *featureGenerations) current(featureKey string) uint64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.gens[featureKey]
+}
+
+// bump starts a new generation of featureKey and runs fn before any
+// evaluation of the previous generation can store its result.
+func (g *featureGenerations) bump(featureKey string, fn func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.gens == nil {
+		g.gens = make(map[string]uint64)
+	}
+	g.gens[featureKey]++
+
+	fn()
+}
+
+// ifCurrent runs fn if gen is still the current generation of featureKey.
+func (g *featureGenerations) ifCurrent(featureKey string, gen uint64, fn func()) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if g.gens[featureKey] != gen {
+		return false
+	}
+	fn()
+
+	return true
+}
+
+type streamState struct {
+	mu          sync.Mutex
+	lastEventID string
+	retry       time.Duration
+	connected   bool
+}
+
+func (s *streamState) setConnected(connected bool) {
+	s.mu.Lock()
+	s.connected = connected
+	s.mu.Unlock()
+}
+
+func (s *streamState) get() (string, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastEventID, s.retry
+}
+
+func (c *Client) runStreamLoop() {
+	defer c.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-c.done
+		cancel()
+	}()
+
+	attempt := 0
+
+	for {
+		received, err := c.streamOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if received {
+			attempt = 0
+		}
+		attempt++
+
+		_, retry := c.stream.get()
+		b := c.cfg.StreamBackoff
+		if retry > 0 && attempt == 1 {
+			b.BaseDelay = retry
+		}
+
+		delay := backoffDelay(b, attempt)
+		delay = delay/2 + mathrand.N(delay/2+1)
+
+		c.logger.Warn("flag change stream disconnected", "error", err, "reconnect_in", delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// streamOnce holds one stream connection open until it fails. It reports
+// whether any event was received, so that backoff can be reset.
+func (c *Client) streamOnce(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// A half-open connection never fails a read, so silence beyond the idle
+	// timeout closes it. Every line, heartbeat comments included, resets it.
+	resetIdle := func() {}
+	if idle := c.cfg.StreamIdleTimeout; idle > 0 {
+		timer := time.AfterFunc(idle, func() { cancel(errStreamIdle) })
+		defer timer.Stop()
+
+		resetIdle = func() { timer.Reset(idle) }
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(c.cfg.BaseURL, "/")+streamPath, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Authorization", c.cfg.APIKey)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	if lastEventID, _ := c.stream.get(); lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		if cause := context.Cause(ctx); errors.Is(cause, errStreamIdle) {
+			return false, cause
+		}
+
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return false, ErrUnauthorized
+	case http.StatusForbidden:
+		return false, ErrForbidden
+	default:
+		return false, &APIError{
+			Code:       "stream_failed",
+			Message:    fmt.Sprintf("unexpected stream status: %s", resp.Status),
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	c.logger.Info("flag change stream connected")
+	c.stream.setConnected(true)
+	defer c.stream.setConnected(false)
+	c.stats.markSuccess()
+
+	received := false
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 4096), streamMaxLineSize)
+
+	var eventType, eventID string
+	var data strings.Builder
+
+	for scanner.Scan() {
+		resetIdle()
+		line := scanner.Text()
+
+		if line == "" {
+			if data.Len() > 0 {
+				received = true
+				c.dispatchStreamEvent(eventType, eventID, data.String())
+			}
+			eventType, eventID = "", ""
+			data.Reset()
+
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		case "id":
+			eventID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				c.stream.mu.Lock()
+				c.stream.retry = time.Duration(ms) * time.Millisecond
+				c.stream.mu.Unlock()
+			}
+		}
+	}
+
+	if cause := context.Cause(ctx); errors.Is(cause, errStreamIdle) {
+		return received, cause
+	}
+
+	if err := scanner.Err(); err != nil {
+		return received, err
+	}
+
+	return received, errors.New("stream closed by server")
+}
+
+func (c *Client) dispatchStreamEvent(eventType, eventID, data string) {
+	if eventType != "" && eventType != streamEventFlagChange {
+		c.logger.Debug("ignoring stream event", "event", eventType)
+
+		return
+	}
+
+	var change FlagChange
+	if err := json.Unmarshal([]byte(data), &change); err != nil {
+		c.logger.Warn("failed to decode flag change", "error", err)
+
+		return
+	}
+	change.EventID = eventID
+
+	if eventID != "" {
+		c.stream.mu.Lock()
+		c.stream.lastEventID = eventID
+		c.stream.mu.Unlock()
+	}
+
+	c.applyFlagChange(change)
+	c.flagChanges.notify(change)
+}
+
+// applyFlagChange drops cached evaluations of the changed feature. Entries
+// tracked by the background refresher are re-evaluated right away.
+func (c *Client) applyFlagChange(change FlagChange) {
+	if c.cache == nil {
+		return
+	}
+
+	var removed int
+	c.gens.bump(change.FeatureKey, func() {
+		removed = c.cache.DeleteFeature(change.FeatureKey)
+	})
+	c.logger.Debug("flag changed, cache invalidated",
+		"feature_key", change.FeatureKey, "kind", change.Kind, "entries", removed)
+
+	if c.hot == nil || change.Kind == FlagChangeDeleted {
+		return
+	}
+
+	due := c.hot.forFeature(change.FeatureKey)
+	if len(due) == 0 {
+		return
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ctx, cancel := c.doneContext()
+		defer cancel()
+
+		forEachLimited(len(due), c.cfg.Refresh.Concurrency, func(i int) {
+			if err := c.refreshEntry(ctx, due[i].featureKey, due[i].req); err != nil {
+				c.logger.Debug("failed to refresh changed flag", "feature_key", due[i].featureKey, "error", err)
+			}
+		})
+	}()
+}
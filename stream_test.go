@@ -0,0 +1,154 @@
+package togglr
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientStreamInvalidatesCache(t *testing.T) {
+	var evaluations, connections atomic.Int32
+	lastEventIDs := make(chan string, 4)
+	release := make(chan struct{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", evaluateHandler(&evaluations, true, "on"))
+	mux.HandleFunc("GET /sdk/v1/stream", func(w http.ResponseWriter, r *http.Request) {
+		n := connections.Add(1)
+		lastEventIDs <- r.Header.Get("Last-Event-ID")
+
+		assert.Equal(t, "test-api-key", r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		if n == 1 {
+			<-release
+			fmt.Fprint(w, ": keep-alive\n\n")
+			fmt.Fprint(w, "retry: 1\n")
+			fmt.Fprint(w, "id: 42\n")
+			fmt.Fprint(w, "event: flag_change\n")
+			fmt.Fprint(w, `data: {"feature_key":"new_ui","kind":"updated","enabled":false,`+"\n")
+			fmt.Fprint(w, `data: "changed_at":"2025-01-02T03:04:05Z"}`+"\n\n")
+			w.(http.Flusher).Flush()
+
+			return
+		}
+
+		<-r.Context().Done()
+	})
+
+	client := newTestClient(t, mux,
+		WithCache(10, time.Minute),
+		WithStream(),
+		WithStreamBackoff(Backoff{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1}),
+	)
+
+	changes := make(chan FlagChange, 1)
+	unsubscribe := client.Subscribe(func(change FlagChange) { changes <- change })
+	defer unsubscribe()
+
+	req := NewContext().WithUserID("u1")
+	client.Evaluate("new_ui", req)
+	client.Evaluate("other", req)
+	require.Equal(t, 2, client.cache.Size())
+
+	assert.Equal(t, "", <-lastEventIDs)
+	close(release)
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, "42", change.EventID)
+		assert.Equal(t, "new_ui", change.FeatureKey)
+		assert.Equal(t, FlagChangeUpdated, change.Kind)
+		require.NotNil(t, change.Enabled)
+		assert.False(t, *change.Enabled)
+		assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), change.ChangedAt)
+	case <-time.After(2 * time.Second):
+		t.Fatal("flag change was not delivered")
+	}
+
+	assert.Equal(t, 1, client.cache.Size())
+
+	select {
+	case id := <-lastEventIDs:
+		assert.Equal(t, "42", id, "reconnect should resume from the last event")
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream did not reconnect")
+	}
+}
+
+func TestClientFlagChangeDuringEvaluationIsNotCached(t *testing.T) {
+	var evaluations atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sdk/v1/features/{feature_key}/evaluate", func(w http.ResponseWriter, r *http.Request) {
+		if evaluations.Load() == 0 {
+			close(started)
+			<-release
+		}
+		evaluateHandler(&evaluations, true, "on")(w, r)
+	})
+
+	client := newTestClient(t, mux, WithCache(10, time.Minute))
+
+	done := make(chan EvalResult)
+	go func() { done <- client.Evaluate("kill_switch", NewContext()) }()
+
+	<-started
+	client.applyFlagChange(FlagChange{FeatureKey: "kill_switch", Kind: FlagChangeUpdated})
+	close(release)
+
+	res := <-done
+	require.NoError(t, res.Err())
+	assert.Zero(t, client.cache.Size(), "a response read before the change must not be cached")
+
+	client.Evaluate("kill_switch", NewContext())
+	assert.Equal(t, 1, client.cache.Size())
+}
+
+func TestClientStreamReconnectsWhenIdle(t *testing.T) {
+	var connections atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sdk/v1/stream", func(w http.ResponseWriter, r *http.Request) {
+		connections.Add(1)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ": connected\n\n")
+		w.(http.Flusher).Flush()
+
+		// Heartbeats keep the connection alive for a while, then the
+		// connection goes silent without being closed.
+		for range 5 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(20 * time.Millisecond):
+			}
+			fmt.Fprint(w, ": heartbeat\n")
+			w.(http.Flusher).Flush()
+		}
+
+		<-r.Context().Done()
+	})
+
+	newTestClient(t, mux,
+		WithStream(),
+		WithStreamIdleTimeout(60*time.Millisecond),
+		WithStreamBackoff(Backoff{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Factor: 1}),
+	)
+
+	time.Sleep(120 * time.Millisecond)
+	assert.Equal(t, int32(1), connections.Load(), "heartbeats must keep the connection open")
+
+	assert.Eventually(t, func() bool { return connections.Load() >= 2 }, 2*time.Second, 5*time.Millisecond)
+}
@@ -12,6 +12,26 @@ func (c *Client) TrackEvent(
 	ctx context.Context,
 	featureKey string,
 	event *TrackEvent,
+) error {
+	_, err := c.withHooks(ctx, OperationTrack, featureKey, event.Context, func(req RequestContext) (*EvalResult, error) {
+		attrs, err := c.prepareAttributes(OperationTrack, featureKey, req)
+		if err != nil {
+			return nil, err
+		}
+
+		e := *event
+		e.Context = attrs
+
+		return nil, c.trackEvent(ctx, featureKey, &e)
+	})
+
+	return err
+}
+
+func (c *Client) trackEvent(
+	ctx context.Context,
+	featureKey string,
+	event *TrackEvent,
 ) error {
 	start := time.Now()
 	c.metrics.IncTrackEventRequest()
@@ -24,6 +44,10 @@ func (c *Client) TrackEvent(
 	c.metrics.ObserveTrackEventLatency(time.Since(start))
 	if err != nil {
 		c.metrics.IncTrackEventError("track_event_failed")
+		c.debug.recordError("track", featureKey, err)
+		c.stats.markFailure(err)
+	} else {
+		c.stats.markSuccess()
 	}
 
 	return err
@@ -48,7 +72,7 @@ func (c *Client) trackEventWithRetries(
 			}
 		}
 
-		apiReq := event.toAPIRequest()
+		apiReq := event.toAPIRequest(c.defaultAttrs, c.logger)
 
 		params := api.TrackFeatureEventParams{
 			FeatureKey: featureKey,
@@ -1,11 +1,8 @@
 package togglr
 
 import (
-	"encoding/json"
 	"time"
 
-	"github.com/go-faster/jx"
-
 	api "github.com/togglr-project/togglr-sdk-go/internal/generated/client"
 )
 
@@ -61,7 +58,9 @@ func (te *TrackEvent) WithDedupKey(dedupKey string) *TrackEvent {
 	return te
 }
 
-func (te *TrackEvent) toAPIRequest() *api.TrackRequest {
+// toAPIRequest builds the request body, with the event context layered over
+// defaults.
+func (te *TrackEvent) toAPIRequest(defaults map[string]any, logger Logger) *api.TrackRequest {
 	req := &api.TrackRequest{
 		VariantKey: te.VariantKey,
 		EventType:  api.TrackRequestEventType(te.EventType),
@@ -71,13 +70,8 @@ func (te *TrackEvent) toAPIRequest() *api.TrackRequest {
 		req.Reward = api.NewOptFloat32(*te.Reward)
 	}
 
-	if len(te.Context) > 0 {
-		contextData := make(api.TrackRequestContext)
-		for k, v := range te.Context {
-			if raw, err := json.Marshal(v); err == nil {
-				contextData[k] = jx.Raw(raw)
-			}
-		}
+	if attrs := mergeAttributes(defaults, te.Context); len(attrs) > 0 {
+		contextData := api.TrackRequestContext(encodeAttributes(attrs, logger))
 		req.Context = api.NewOptTrackRequestContext(contextData)
 	}
 
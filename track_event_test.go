@@ -42,7 +42,7 @@ func TestToAPIRequest(t *testing.T) {
 		WithCreatedAt(createdAt).
 		WithDedupKey("dedup-123")
 
-	req := te.toAPIRequest()
+	req := te.toAPIRequest(nil, &NoOpLogger{})
 	assert.Equal(t, "test-variant", req.VariantKey)
 	assert.Equal(t, api.TrackRequestEventType(EventTypeSuccess), req.EventType)
 	vReward, okReward := req.Reward.Get()
@@ -59,7 +59,7 @@ func TestToAPIRequest(t *testing.T) {
 
 func TestToAPIRequestEmptyFields(t *testing.T) {
 	te := NewTrackEvent("test-variant", EventTypeSuccess)
-	req := te.toAPIRequest()
+	req := te.toAPIRequest(nil, &NoOpLogger{})
 	assert.Equal(t, "test-variant", req.VariantKey)
 	assert.Equal(t, api.TrackRequestEventType(EventTypeSuccess), req.EventType)
 	_, okRewardEmpty := req.Reward.Get()
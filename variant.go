@@ -0,0 +1,75 @@
+package togglr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// VariantControl is the handler key RunVariant falls back to when there is no
+// handler for the evaluated variant, or the feature is disabled.
+const VariantControl = "control"
+
+var (
+	// ErrVariantFailure marks a handler outcome as a failure rather than an
+	// error, e.g. a user who did not convert. Wrap it to add detail.
+	ErrVariantFailure   = errors.New("variant failure")
+	ErrNoVariantHandler = errors.New("no handler for variant")
+)
+
+// RunVariant evaluates featureKey and runs the handler of the evaluated
+// variant, or the VariantControl handler if there is none. When the handler
+// of a variant assigned by the server ran, its outcome is tracked in the
+// background with the returned reward and req as context: success for a nil
+// error, failure for ErrVariantFailure and error otherwise. Variants taken
+// from a default or a stale snapshot entry run but are not tracked. The
+// handler error is returned.
+func (c *Client) RunVariant(
+	ctx context.Context,
+	featureKey string,
+	req RequestContext,
+	handlers map[string]func(ctx context.Context) (reward float32, err error),
+) error {
+	res := c.EvaluateWithContext(ctx, featureKey, req)
+	variant := res.Value()
+
+	handler, matched := handlers[variant]
+	if !matched || variant == "" {
+		matched = false
+
+		var ok bool
+		if handler, ok = handlers[VariantControl]; !ok {
+			return fmt.Errorf("%w %q of %s", ErrNoVariantHandler, variant, featureKey)
+		}
+
+		c.logger.Debug("running control variant",
+			"feature_key", featureKey, "variant", variant, "error", res.Err())
+	}
+
+	reward, err := handler(ctx)
+	if !matched {
+		return err
+	}
+
+	if !res.fromServer() {
+		c.logger.Debug("variant not assigned by the server, outcome not tracked",
+			"feature_key", featureKey, "variant", variant, "source", res.Source())
+
+		return err
+	}
+
+	eventType := EventTypeSuccess
+	switch {
+	case errors.Is(err, ErrVariantFailure):
+		eventType = EventTypeFailure
+	case err != nil:
+		eventType = EventTypeError
+	}
+
+	event := NewTrackEvent(variant, eventType).
+		WithReward(reward).
+		WithContexts(req)
+	c.sendOutcome(ctx, featureKey, nil, event)
+
+	return err
+}
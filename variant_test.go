@@ -0,0 +1,114 @@
+package togglr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRunVariant(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		variant    string
+		wantRan    string
+		wantErr    error
+		wantEvent  string
+		wantReward float64
+	}{
+		{name: "success", enabled: true, variant: "A", wantRan: "A", wantEvent: "success", wantReward: 1},
+		{name: "failure", enabled: true, variant: "B", wantRan: "B", wantErr: ErrVariantFailure, wantEvent: "failure"},
+		{name: "error", enabled: true, variant: "C", wantRan: "C", wantErr: errBoom, wantEvent: "error"},
+		{name: "unknown variant runs control", enabled: true, variant: "D", wantRan: VariantControl},
+		{name: "disabled runs control", enabled: false, variant: "A", wantRan: VariantControl},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := &recordingServer{}
+			client := newTestClient(t, srv.handler(&calls, tt.enabled, tt.variant))
+
+			var ran string
+			handler := func(name string, reward float32, err error) func(context.Context) (float32, error) {
+				return func(context.Context) (float32, error) {
+					ran = name
+
+					return reward, err
+				}
+			}
+
+			err := client.RunVariant(context.Background(), "exp", NewContext().WithUserID("u1"),
+				map[string]func(context.Context) (float32, error){
+					"A":            handler("A", 1, nil),
+					"B":            handler("B", 0, fmt.Errorf("no purchase: %w", ErrVariantFailure)),
+					"C":            handler("C", 0, errBoom),
+					VariantControl: handler(VariantControl, 0, nil),
+				})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantRan, ran)
+			require.NoError(t, client.Close())
+
+			if tt.wantEvent == "" {
+				assert.Empty(t, srv.tracks)
+
+				return
+			}
+
+			require.Len(t, srv.tracks, 1)
+			assert.Equal(t, tt.variant, srv.tracks[0]["variant_key"])
+			assert.Equal(t, tt.wantEvent, srv.tracks[0]["event_type"])
+			assert.Equal(t, tt.wantReward, srv.tracks[0]["reward"])
+			assert.Equal(t, map[string]any{AttrUserID: "u1"}, srv.tracks[0]["context"])
+		})
+	}
+}
+
+func TestClientRunVariantDefaultNotTracked(t *testing.T) {
+	var calls atomic.Int32
+	srv := &recordingServer{}
+	mux := srv.handler(&calls, true, "A")
+	mux.HandleFunc("POST /sdk/v1/features/exp/evaluate", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	client := newTestClient(t, mux,
+		WithRetries(0),
+		WithDefaults(map[string]DefaultValue{"exp": {Enabled: true, Value: "A"}}),
+	)
+
+	var ran bool
+	err := client.RunVariant(context.Background(), "exp", NewContext(), map[string]func(context.Context) (float32, error){
+		"A": func(context.Context) (float32, error) {
+			ran = true
+
+			return 1, nil
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	assert.True(t, ran)
+	assert.Empty(t, srv.tracks)
+}
+
+func TestClientRunVariantWithoutControl(t *testing.T) {
+	var calls atomic.Int32
+	srv := &recordingServer{}
+	client := newTestClient(t, srv.handler(&calls, true, "Z"))
+
+	err := client.RunVariant(context.Background(), "exp", NewContext(), map[string]func(context.Context) (float32, error){
+		"A": func(context.Context) (float32, error) { return 0, nil },
+	})
+
+	assert.ErrorIs(t, err, ErrNoVariantHandler)
+}
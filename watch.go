@@ -0,0 +1,156 @@
+package togglr
+
+import (
+	"context"
+	mathrand "math/rand/v2"
+	"sync"
+	"time"
+)
+
+// OnChange calls fn whenever the evaluation of featureKey for req changes,
+// that is when its enabled state or raw value differs from the previous
+// successful evaluation. Evaluations are repeated every Config.WatchInterval
+// (plus up to Config.WatchJitter) and, when the change stream is enabled, as
+// soon as the feature changes on the server.
+//
+// fn is not called after unsubscribe returns: unsubscribe waits for a call in
+// progress, so it must not be called from fn. After Close, OnChange does
+// nothing.
+func (c *Client) OnChange(
+	featureKey string,
+	req RequestContext,
+	fn func(old, new EvalResult),
+) (unsubscribe func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	stopped := false
+
+	started := c.goBackground(func() {
+		c.watch(ctx, featureKey, req, func(old, new EvalResult, initial bool) bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if stopped {
+				return false
+			}
+
+			if !initial {
+				fn(old, new)
+			}
+
+			return true
+		})
+	})
+	if !started {
+		cancel()
+	}
+
+	return func() {
+		cancel()
+
+		mu.Lock()
+		stopped = true
+		mu.Unlock()
+	}
+}
+
+// Watch returns a channel that receives the current evaluation of featureKey
+// for req and then every change of it. The channel is closed when ctx is done
+// or the client is closed; after Close it is returned closed.
+func (c *Client) Watch(ctx context.Context, featureKey string, req RequestContext) <-chan EvalResult {
+	ch := make(chan EvalResult, 1)
+
+	started := c.goBackground(func() {
+		defer close(ch)
+
+		c.watch(ctx, featureKey, req, func(_, new EvalResult, _ bool) bool {
+			select {
+			case ch <- new:
+				return true
+			case <-ctx.Done():
+				return false
+			case <-c.done:
+				return false
+			}
+		})
+	})
+	if !started {
+		close(ch)
+	}
+
+	return ch
+}
+
+func (c *Client) watch(
+	ctx context.Context,
+	featureKey string,
+	req RequestContext,
+	emit func(old, new EvalResult, initial bool) bool,
+) {
+	changed := make(chan struct{}, 1)
+	unsubscribe := c.Subscribe(func(change FlagChange) {
+		if change.FeatureKey != featureKey {
+			return
+		}
+
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	var last EvalResult
+	initialized := false
+
+	for {
+		res := c.EvaluateWithContext(ctx, featureKey, req)
+
+		switch {
+		// Stale snapshot values are the last known server values and are
+		// emitted; defaults are not.
+		case res.Err() != nil && res.Source() != SourceStale:
+			c.logger.Debug("watch evaluation failed", "feature_key", featureKey, "error", res.Err())
+		case !initialized:
+			if !emit(EvalResult{}, res, true) {
+				return
+			}
+			last, initialized = res, true
+		case res.enabled != last.enabled || res.rawValue != last.rawValue:
+			if !emit(last, res, false) {
+				return
+			}
+			last = res
+		}
+
+		timer := time.NewTimer(c.watchDelay())
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-c.done:
+			timer.Stop()
+
+			return
+		case <-changed:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) watchDelay() time.Duration {
+	delay := c.cfg.WatchInterval
+	if delay <= 0 {
+		delay = DefaultConfig("").WatchInterval
+	}
+
+	if c.cfg.WatchJitter > 0 {
+		delay += mathrand.N(c.cfg.WatchJitter)
+	}
+
+	return delay
+}
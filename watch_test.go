@@ -0,0 +1,128 @@
+package togglr
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flagServer struct {
+	mu      sync.Mutex
+	enabled bool
+	value   string
+	calls   atomic.Int32
+}
+
+func (s *flagServer) set(enabled bool, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.enabled, s.value = enabled, value
+}
+
+func (s *flagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.calls.Add(1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"feature_key": r.PathValue("feature_key"),
+		"enabled":     s.enabled,
+		"value":       s.value,
+	})
+}
+
+func newFlagServerMux(s *flagServer) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("POST /sdk/v1/features/{feature_key}/evaluate", s)
+
+	return mux
+}
+
+func TestClientOnChange(t *testing.T) {
+	flags := &flagServer{enabled: true, value: "10"}
+	client := newTestClient(t, newFlagServerMux(flags), WithWatchInterval(5*time.Millisecond, time.Millisecond))
+
+	type change struct{ old, new string }
+	changes := make(chan change, 10)
+
+	unsubscribe := client.OnChange("pool_size", NewContext(), func(old, new EvalResult) {
+		changes <- change{old.Value(), new.Value()}
+	})
+	defer unsubscribe()
+
+	for flags.calls.Load() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Empty(t, changes, "unchanged values must not fire")
+
+	flags.set(true, "20")
+
+	select {
+	case c := <-changes:
+		assert.Equal(t, change{"10", "20"}, c)
+	case <-time.After(time.Second):
+		t.Fatal("change was not reported")
+	}
+
+	unsubscribe()
+	flags.set(false, "20")
+	time.Sleep(30 * time.Millisecond)
+	assert.Empty(t, changes, "unsubscribed callback must not fire")
+}
+
+func TestClientWatch(t *testing.T) {
+	flags := &flagServer{enabled: false, value: ""}
+	client := newTestClient(t, newFlagServerMux(flags), WithWatchInterval(5*time.Millisecond, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := client.Watch(ctx, "new_ui", NewContext().WithUserID("u1"))
+
+	res := <-ch
+	assert.False(t, res.Enabled())
+
+	flags.set(true, "B")
+
+	res = <-ch
+	require.NoError(t, res.Err())
+	assert.True(t, res.Enabled())
+	assert.Equal(t, "B", res.Value())
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestClientWatchAfterClose(t *testing.T) {
+	flags := &flagServer{enabled: true, value: "10"}
+	client := newTestClient(t, newFlagServerMux(flags), WithWatchInterval(time.Millisecond, 0))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for range client.Watch(context.Background(), "pool_size", NewContext()) {
+			}
+		}()
+	}
+
+	require.NoError(t, client.Close())
+	wg.Wait()
+
+	_, open := <-client.Watch(context.Background(), "pool_size", NewContext())
+	assert.False(t, open, "Watch after Close must return a closed channel")
+
+	called := false
+	unsubscribe := client.OnChange("pool_size", NewContext(), func(_, _ EvalResult) { called = true })
+	unsubscribe()
+	assert.False(t, called)
+}